@@ -49,6 +49,7 @@ do
           "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
           "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
           "github.com/cloudevents/sdk-go/protocol/ws/v2"
+          "github.com/cloudevents/sdk-go/protocol/sse/v2"
           "github.com/cloudevents/sdk-go/observability/opencensus/v2"
           "github.com/cloudevents/sdk-go/observability/opentelemetry/v2"
           "github.com/cloudevents/sdk-go/sql/v2"
@@ -115,6 +116,7 @@ MODULES=(
   "protocol/pubsub"
   "protocol/kafka_sarama"
   "protocol/ws"
+  "protocol/sse"
   "observability/opencensus"
   "sql"
   "binding/format/protobuf"
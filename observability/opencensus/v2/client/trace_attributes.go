@@ -9,6 +9,7 @@ import (
 	"go.opencensus.io/trace"
 
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/observability"
 )
 
@@ -25,5 +26,13 @@ func EventTraceAttributes(e event.EventReader) []trace.Attribute {
 	if dct := e.DataContentType(); dct != "" {
 		as = append(as, trace.StringAttribute(observability.DatacontenttypeAttr, dct))
 	}
+	if lineage, ok := extensions.GetLineageExtension(e); ok {
+		if lineage.CausationID != "" {
+			as = append(as, trace.StringAttribute(observability.CausationidAttr, lineage.CausationID))
+		}
+		if lineage.CorrelationID != "" {
+			as = append(as, trace.StringAttribute(observability.CorrelationidAttr, lineage.CorrelationID))
+		}
+	}
 	return as
 }
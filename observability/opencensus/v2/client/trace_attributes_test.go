@@ -0,0 +1,37 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opencensus.io/trace"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/observability"
+)
+
+func TestEventTraceAttributes_Lineage(t *testing.T) {
+	e := event.New()
+	e.SetID("child")
+	e.SetType("type")
+	e.SetSource("/source")
+	extensions.LineageExtension{CausationID: "parent", CorrelationID: "root"}.AddLineageAttributes(&e)
+
+	want := []trace.Attribute{
+		trace.StringAttribute(observability.SpecversionAttr, "1.0"),
+		trace.StringAttribute(observability.IdAttr, "child"),
+		trace.StringAttribute(observability.TypeAttr, "type"),
+		trace.StringAttribute(observability.SourceAttr, "/source"),
+		trace.StringAttribute(observability.CausationidAttr, "parent"),
+		trace.StringAttribute(observability.CorrelationidAttr, "root"),
+	}
+	if !reflect.DeepEqual(want, EventTraceAttributes(&e)) {
+		t.Errorf("unexpected trace attributes: %v", EventTraceAttributes(&e))
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/observability"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
@@ -68,6 +69,36 @@ func (o OTelObservabilityService) RecordReceivedMalformedEvent(ctx context.Conte
 	span.End()
 }
 
+// RecordRejectedRequest records the reason of the rejection of a request by the protocol in a new span.
+func (o OTelObservabilityService) RecordRejectedRequest(ctx context.Context, err error) {
+	spanName := observability.ClientSpanName + ".rejected receive"
+	_, span := o.tracer.Start(
+		ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String(string(semconv.CodeFunctionKey), getFuncName())))
+
+	recordSpanError(span, err)
+	span.End()
+}
+
+// RecordValidationWarning records the validation errors of an event accepted by the lenient validation
+// as an event of the current span, or of a new span if there's none.
+func (o OTelObservabilityService) RecordValidationWarning(ctx context.Context, event *cloudevents.Event, err error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		spanName := observability.ClientSpanName + ".validation warning"
+		_, span = o.tracer.Start(
+			ctx, spanName,
+			trace.WithAttributes(attribute.String(string(semconv.CodeFunctionKey), getFuncName())))
+		defer span.End()
+	}
+
+	span.AddEvent("validation warning", trace.WithAttributes(
+		attribute.String(observability.IdAttr, event.ID()),
+		semconv.ExceptionMessageKey.String(err.Error()),
+	))
+}
+
 // RecordCallingInvoker starts a new span before calling the invoker upon a received event.
 // In case the operation fails, the error is recorded and the span is marked as failed.
 func (o OTelObservabilityService) RecordCallingInvoker(ctx context.Context, event *cloudevents.Event) (context.Context, func(errOrResult error)) {
@@ -143,6 +174,14 @@ func GetDefaultSpanAttributes(e *cloudevents.Event, method string) []attribute.K
 	if dct := e.DataContentType(); dct != "" {
 		attr = append(attr, attribute.String(observability.DatacontenttypeAttr, dct))
 	}
+	if lineage, ok := extensions.GetLineageExtension(e); ok {
+		if lineage.CausationID != "" {
+			attr = append(attr, attribute.String(observability.CausationidAttr, lineage.CausationID))
+		}
+		if lineage.CorrelationID != "" {
+			attr = append(attr, attribute.String(observability.CorrelationidAttr, lineage.CorrelationID))
+		}
+	}
 	return attr
 }
 
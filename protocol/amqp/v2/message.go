@@ -16,9 +16,13 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 )
 
-const prefix = "cloudEvents:" // Name prefix for AMQP properties that hold CE attributes.
+const (
+	prefix                 = "cloudEvents:"        // Name prefix for AMQP properties that hold CE attributes.
+	partitionKeyAnnotation = "x-opt-partition-key" // Message annotation used by brokers for partitioning.
+)
 
 var (
 	// Use the package path as AMQP error condition name
@@ -103,6 +107,15 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 		}
 	}
 
+	if _, ok := m.AMQP.ApplicationProperties[prefix+extensions.PartitionKeyExtension]; !ok {
+		if pk, ok := m.AMQP.Annotations[partitionKeyAnnotation]; ok {
+			err = encoder.SetExtension(extensions.PartitionKeyExtension, pk)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	data := m.AMQP.GetData()
 	if len(data) != 0 { // Some data
 		err = encoder.SetData(bytes.NewBuffer(data))
@@ -122,7 +135,13 @@ func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
 }
 
 func (m *Message) GetExtension(name string) interface{} {
-	return m.AMQP.ApplicationProperties[prefix+name]
+	if v, ok := m.AMQP.ApplicationProperties[prefix+name]; ok {
+		return v
+	}
+	if name == extensions.PartitionKeyExtension {
+		return m.AMQP.Annotations[partitionKeyAnnotation]
+	}
+	return nil
 }
 
 func (m *Message) Finish(err error) error {
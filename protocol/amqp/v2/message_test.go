@@ -14,6 +14,7 @@ import (
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	. "github.com/cloudevents/sdk-go/v2/test"
 )
 
@@ -59,3 +60,20 @@ func TestNewMessage_message_unknown(t *testing.T) {
 	got := NewMessage(message)
 	require.Equal(t, binding.EncodingUnknown, got.ReadEncoding())
 }
+
+func TestPartitionKeyAnnotation(t *testing.T) {
+	eventIn := MinEvent()
+	eventIn.SetExtension(extensions.PartitionKeyExtension, "customer-42")
+
+	message := amqp.Message{}
+	require.NoError(t, WriteMessage(binding.WithForceBinary(context.TODO()), binding.ToMessage(&eventIn), &message))
+	require.Equal(t, "customer-42", message.Annotations[partitionKeyAnnotation])
+
+	// A message sent by a non CloudEvents producer only carries the annotation
+	delete(message.ApplicationProperties, prefix+extensions.PartitionKeyExtension)
+	got, err := binding.ToEvent(context.TODO(), NewMessage(&message))
+	require.NoError(t, err)
+	pe, ok := extensions.GetPartitioningExtension(*got)
+	require.True(t, ok)
+	require.Equal(t, "customer-42", pe.PartitionKey)
+}
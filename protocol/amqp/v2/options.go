@@ -7,6 +7,8 @@ package amqp
 
 import (
 	"github.com/Azure/go-amqp"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
 )
 
 // Option is the function signature required to be considered an amqp.Option.
@@ -49,5 +51,15 @@ func WithReceiverLinkOption(opt amqp.LinkOption) Option {
 	}
 }
 
+// WithReceiverLimits bounds the size, the number of extensions and the length of the extension values
+// of the received events. Reading a message exceeding the limits fails, so the message is rejected
+// and can be dead-lettered by the broker.
+func WithReceiverLimits(limits binding.Limits) Option {
+	return func(t *Protocol) error {
+		t.receiverLimits = &limits
+		return nil
+	}
+}
+
 // SenderOptionFunc is the type of amqp.Sender options
 type SenderOptionFunc func(sender *sender)
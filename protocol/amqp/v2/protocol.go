@@ -31,7 +31,8 @@ type Protocol struct {
 	SenderContextDecorators []func(context.Context) context.Context
 
 	// Receiver
-	Receiver *receiver
+	Receiver       *receiver
+	receiverLimits *binding.Limits
 }
 
 // NewProtocolFromClient creates a new amqp transport.
@@ -65,6 +66,7 @@ func NewProtocolFromClient(client *amqp.Client, session *amqp.Session, queue str
 		return nil, err
 	}
 	t.Receiver = NewReceiver(amqpReceiver).(*receiver)
+	t.Receiver.limits = t.receiverLimits
 	return t, nil
 }
 
@@ -137,6 +139,7 @@ func NewReceiverProtocolFromClient(client *amqp.Client, session *amqp.Session, a
 		return nil, err
 	}
 	t.Receiver = NewReceiver(amqpReceiver).(*receiver)
+	t.Receiver.limits = t.receiverLimits
 	return t, nil
 }
 
@@ -15,8 +15,17 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
+// amqpReceiver is the subset of *amqp.Receiver used by receiver
+type amqpReceiver interface {
+	Receive(ctx context.Context) (*amqp.Message, error)
+	Close(ctx context.Context) error
+}
+
 // receiver wraps an amqp.Receiver as a binding.Receiver
-type receiver struct{ amqp *amqp.Receiver }
+type receiver struct {
+	amqp   amqpReceiver
+	limits *binding.Limits
+}
 
 func (r *receiver) Receive(ctx context.Context) (binding.Message, error) {
 	m, err := r.amqp.Receive(ctx)
@@ -27,6 +36,9 @@ func (r *receiver) Receive(ctx context.Context) (binding.Message, error) {
 		return nil, err
 	}
 
+	if r.limits != nil {
+		return binding.WithLimits(NewMessage(m), *r.limits), nil
+	}
 	return NewMessage(m), nil
 }
 
@@ -0,0 +1,62 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-amqp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type amqpReceiverMock chan *amqp.Message
+
+func (r amqpReceiverMock) Receive(ctx context.Context) (*amqp.Message, error) {
+	select {
+	case m := <-r:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r amqpReceiverMock) Close(context.Context) error {
+	return nil
+}
+
+func TestWithReceiverLimits(t *testing.T) {
+	p := &Protocol{}
+	require.NoError(t, p.applyOptions(WithReceiverLimits(binding.Limits{MaxEventSize: 100})))
+	messages := make(amqpReceiverMock, 1)
+	r := &receiver{amqp: messages, limits: p.receiverLimits}
+
+	for _, size := range []int{10, 1000} {
+		e := event.New()
+		e.SetID("id")
+		e.SetType("type")
+		e.SetSource("source")
+		require.NoError(t, e.SetData(event.TextPlain, strings.Repeat("a", size)))
+		ctx := binding.WithForceBinary(context.Background())
+		message := amqp.Message{}
+		require.NoError(t, WriteMessage(ctx, binding.ToMessage(&e), &message))
+		messages <- &message
+
+		m, err := r.Receive(context.Background())
+		require.NoError(t, err)
+		_, err = binding.ToEvent(context.Background(), m)
+		if size < 100 {
+			require.NoError(t, err)
+		} else {
+			require.True(t, errors.Is(err, binding.ErrLimitExceeded), "unexpected error %v", err)
+		}
+	}
+}
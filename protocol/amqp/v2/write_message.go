@@ -15,15 +15,20 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
 // WriteMessage fills the provided amqpMessage with the message m.
 // Using context you can tweak the encoding processing (more details on binding.Write documentation).
+// The partitionkey extension, if present, is also written to the x-opt-partition-key message annotation.
 func WriteMessage(ctx context.Context, m binding.Message, amqpMessage *amqp.Message, transformers ...binding.Transformer) error {
 	structuredWriter := (*amqpMessageWriter)(amqpMessage)
 	binaryWriter := (*amqpMessageWriter)(amqpMessage)
 
+	var pe extensions.PartitioningExtension
+	transformers = append(transformers, pe.ReadTransformer())
+
 	_, err := binding.Write(
 		ctx,
 		m,
@@ -31,6 +36,12 @@ func WriteMessage(ctx context.Context, m binding.Message, amqpMessage *amqp.Mess
 		binaryWriter,
 		transformers...,
 	)
+	if err == nil && pe.PartitionKey != "" {
+		if amqpMessage.Annotations == nil {
+			amqpMessage.Annotations = make(amqp.Annotations)
+		}
+		amqpMessage.Annotations[partitionKeyAnnotation] = pe.PartitionKey
+	}
 	return err
 }
 
@@ -13,13 +13,16 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 
 	"github.com/Shopify/sarama"
 )
 
 const (
-	prefix            = "ce_"
-	contentTypeHeader = "content-type"
+	prefix             = "ce_"
+	contentTypeHeader  = "content-type"
+	partitionKeyHeader = prefix + extensions.PartitionKeyExtension
 )
 
 var specs = spec.WithPrefix(prefix)
@@ -30,8 +33,12 @@ type Message struct {
 	Value       []byte
 	Headers     map[string][]byte
 	ContentType string
-	format      format.Format
-	version     spec.Version
+	// Key is the record key, exposed as the partitionkey extension when the event has none:
+	// in binary mode when no partitionkey header is present, and in structured mode when the event encoded
+	// in the value has no partitionkey extension.
+	Key     []byte
+	format  format.Format
+	version spec.Version
 }
 
 // Check if http.Message implements binding.Message
@@ -39,6 +46,7 @@ var _ binding.Message = (*Message)(nil)
 var _ binding.MessageMetadataReader = (*Message)(nil)
 
 // NewMessageFromConsumerMessage returns a binding.Message that holds the provided ConsumerMessage.
+// The key of the ConsumerMessage is mapped to the partitionkey extension, see Message.Key.
 // The returned binding.Message *can* be read several times safely
 // This function *doesn't* guarantee that the returned binding.Message is always a kafka_sarama.Message instance
 func NewMessageFromConsumerMessage(cm *sarama.ConsumerMessage) *Message {
@@ -51,7 +59,9 @@ func NewMessageFromConsumerMessage(cm *sarama.ConsumerMessage) *Message {
 		}
 		headers[k] = r.Value
 	}
-	return NewMessage(cm.Value, contentType, headers)
+	m := NewMessage(cm.Value, contentType, headers)
+	m.Key = cm.Key
+	return m
 }
 
 // NewMessage returns a binding.Message that holds the provided kafka message components.
@@ -93,11 +103,33 @@ func (m *Message) ReadEncoding() binding.Encoding {
 
 func (m *Message) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
 	if m.format != nil {
-		return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.Value))
+		return encoder.SetStructuredEvent(ctx, m.format, bytes.NewReader(m.structuredValue()))
 	}
 	return binding.ErrNotStructured
 }
 
+// structuredValue returns the structured event of the value, with the record key as partitionkey extension
+// if the event has none. The value is returned as it is when it can't be decoded, so the error is reported
+// by the structured writer.
+func (m *Message) structuredValue() []byte {
+	if len(m.Key) == 0 {
+		return m.Value
+	}
+	e := event.New()
+	if err := m.format.Unmarshal(m.Value, &e); err != nil {
+		return m.Value
+	}
+	if _, ok := e.Extensions()[extensions.PartitionKeyExtension]; ok {
+		return m.Value
+	}
+	e.SetExtension(extensions.PartitionKeyExtension, string(m.Key))
+	value, err := m.format.Marshal(&e)
+	if err != nil {
+		return m.Value
+	}
+	return value
+}
+
 func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter) (err error) {
 	if m.version == nil {
 		return binding.ErrNotBinary
@@ -119,6 +151,12 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 		}
 	}
 
+	if _, ok := m.Headers[partitionKeyHeader]; !ok && len(m.Key) > 0 {
+		if err = encoder.SetExtension(extensions.PartitionKeyExtension, string(m.Key)); err != nil {
+			return
+		}
+	}
+
 	if m.Value != nil {
 		err = encoder.SetData(bytes.NewBuffer(m.Value))
 	}
@@ -135,7 +173,13 @@ func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
 }
 
 func (m *Message) GetExtension(name string) interface{} {
-	return string(m.Headers[prefix+name])
+	if v, ok := m.Headers[prefix+name]; ok {
+		return string(v)
+	}
+	if name == extensions.PartitionKeyExtension && len(m.Key) > 0 {
+		return string(m.Key)
+	}
+	return ""
 }
 
 func (m *Message) Finish(error) error {
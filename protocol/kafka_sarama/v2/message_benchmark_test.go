@@ -44,3 +44,13 @@ func BenchmarkNewBinaryMessageToEvent(b *testing.B) {
 		Event, Err = binding.ToEvent(context.TODO(), M)
 	}
 }
+
+func BenchmarkNewBinaryMessageToPooledEvent(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var release func()
+		M = kafka_sarama.NewMessageFromConsumerMessage(binaryConsumerMessage)
+		Event, release, Err = binding.ToPooledEvent(context.TODO(), M)
+		Err = Event.Validate()
+		release()
+	}
+}
@@ -6,6 +6,7 @@
 package kafka_sarama_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Shopify/sarama"
@@ -15,6 +16,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/test"
 )
 
@@ -83,6 +85,56 @@ func TestNewMessage(t *testing.T) {
 	}
 }
 
+func TestNewMessageFromConsumerMessage_PartitionKey(t *testing.T) {
+	keyed := *binaryConsumerMessage
+	keyed.Key = []byte("customer-42")
+
+	m := kafka_sarama.NewMessageFromConsumerMessage(&keyed)
+	require.Equal(t, "customer-42", m.GetExtension(extensions.PartitionKeyExtension))
+
+	e, err := binding.ToEvent(context.TODO(), m)
+	require.NoError(t, err)
+	pe, ok := extensions.GetPartitioningExtension(*e)
+	require.True(t, ok)
+	require.Equal(t, "customer-42", pe.PartitionKey)
+
+	// The partitionkey header takes precedence over the record key
+	keyed.Headers = append(mustToSaramaConsumerHeaders(map[string]string{"ce_partitionkey": "from-header"}), keyed.Headers...)
+	m = kafka_sarama.NewMessageFromConsumerMessage(&keyed)
+	e, err = binding.ToEvent(context.TODO(), m)
+	require.NoError(t, err)
+	pe, ok = extensions.GetPartitioningExtension(*e)
+	require.True(t, ok)
+	require.Equal(t, "from-header", pe.PartitionKey)
+}
+
+func TestNewMessageFromConsumerMessage_PartitionKeyStructured(t *testing.T) {
+	keyed := *structuredConsumerMessage
+	keyed.Key = []byte("customer-42")
+
+	m := kafka_sarama.NewMessageFromConsumerMessage(&keyed)
+	require.Equal(t, binding.EncodingStructured, m.ReadEncoding())
+	e, err := binding.ToEvent(context.TODO(), m)
+	require.NoError(t, err)
+	pe, ok := extensions.GetPartitioningExtension(*e)
+	require.True(t, ok)
+	require.Equal(t, "customer-42", pe.PartitionKey)
+	require.Equal(t, testEvent.ID(), e.ID())
+	require.Equal(t, testEvent.Data(), e.Data())
+
+	// The partitionkey extension of the event takes precedence over the record key
+	withPartitionKey := testEvent.Clone()
+	withPartitionKey.SetExtension(extensions.PartitionKeyExtension, "from-event")
+	keyed.Value, err = format.JSON.Marshal(&withPartitionKey)
+	require.NoError(t, err)
+	m = kafka_sarama.NewMessageFromConsumerMessage(&keyed)
+	e, err = binding.ToEvent(context.TODO(), m)
+	require.NoError(t, err)
+	pe, ok = extensions.GetPartitioningExtension(*e)
+	require.True(t, ok)
+	require.Equal(t, "from-event", pe.PartitionKey)
+}
+
 func mustToSaramaConsumerHeaders(m map[string]string) []*sarama.RecordHeader {
 	res := make([]*sarama.RecordHeader, len(m))
 	i := 0
@@ -7,6 +7,10 @@ package kafka_sarama
 
 import (
 	"context"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
 )
 
 // SenderOptionFunc is the type of kafka_sarama.Sender options
@@ -26,3 +30,22 @@ func WithSenderContextDecorators(decorator func(context.Context) context.Context
 		protocol.SenderContextDecorators = append(protocol.SenderContextDecorators, decorator)
 	}
 }
+
+// WithReceiverLimits bounds the size, the number of extensions and the length of the extension values
+// of the received events. Reading a message exceeding the limits fails, so the message is NACKed: its offset
+// isn't marked, but the offsets of the messages accepted after it in the partition are committed past it,
+// so it's effectively skipped and never redelivered. See WithReceiverDeadLetter to keep the skipped messages.
+func WithReceiverLimits(limits binding.Limits) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.receiverLimits = &limits
+	}
+}
+
+// WithReceiverDeadLetter sets a func invoked with the received messages which are NACKed, along with the result
+// of their processing. Since the offsets of the messages accepted after them are committed, the NACKed messages
+// are never redelivered: fn can forward them to a dead letter topic.
+func WithReceiverDeadLetter(fn func(msg *sarama.ConsumerMessage, err error)) ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.receiverDeadLetter = fn
+	}
+}
@@ -40,6 +40,9 @@ type Protocol struct {
 	// Consumer options
 	receiverTopic   string
 	receiverGroupId string
+	receiverLimits  *binding.Limits
+	// receiverDeadLetter is invoked with the NACKed messages
+	receiverDeadLetter func(*sarama.ConsumerMessage, error)
 }
 
 // NewProtocol creates a new kafka transport.
@@ -87,6 +90,8 @@ func NewProtocolFromClient(client sarama.Client, sendToTopic string, receiveFrom
 		return nil, errors.New("you didn't specify the topic to receive from")
 	}
 	p.Consumer = NewConsumerFromClient(p.Client, p.receiverGroupId, p.receiverTopic)
+	p.Consumer.limits = p.receiverLimits
+	p.Consumer.deadLetter = p.receiverDeadLetter
 
 	return p, nil
 }
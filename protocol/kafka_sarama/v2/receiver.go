@@ -26,6 +26,9 @@ type msgErr struct {
 type Receiver struct {
 	once     sync.Once
 	incoming chan msgErr
+	limits   *binding.Limits
+	// deadLetter is invoked with the NACKed messages, if not nil
+	deadLetter func(*sarama.ConsumerMessage, error)
 }
 
 // NewReceiver creates a Receiver which implements sarama.ConsumerGroupHandler
@@ -55,12 +58,17 @@ func (r *Receiver) Close(context.Context) error {
 func (r *Receiver) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	for message := range claim.Messages() {
 		msg := message
-		m := NewMessageFromConsumerMessage(msg)
+		var m binding.Message = NewMessageFromConsumerMessage(msg)
+		if r.limits != nil {
+			m = binding.WithLimits(m, *r.limits)
+		}
 
 		r.incoming <- msgErr{
 			msg: binding.WithFinish(m, func(err error) {
 				if protocol.IsACK(err) {
 					session.MarkMessage(msg, "")
+				} else if r.deadLetter != nil {
+					r.deadLetter(msg, err)
 				}
 			}),
 		}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+type consumerGroupSessionMock struct {
+	sarama.ConsumerGroupSession
+	marked []*sarama.ConsumerMessage
+}
+
+func (s *consumerGroupSessionMock) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, msg)
+}
+
+type consumerGroupClaimMock struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *consumerGroupClaimMock) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func TestWithReceiverLimits(t *testing.T) {
+	p := &Protocol{}
+	var deadLetters []*sarama.ConsumerMessage
+	require.NoError(t, p.applyOptions(
+		WithReceiverLimits(binding.Limits{MaxEventSize: 100}),
+		WithReceiverDeadLetter(func(msg *sarama.ConsumerMessage, err error) {
+			require.True(t, errors.Is(err, binding.ErrLimitExceeded), "unexpected error %v", err)
+			deadLetters = append(deadLetters, msg)
+		}),
+	))
+	r := NewReceiver()
+	r.limits = p.receiverLimits
+	r.deadLetter = p.receiverDeadLetter
+
+	session := &consumerGroupSessionMock{}
+	claim := &consumerGroupClaimMock{messages: make(chan *sarama.ConsumerMessage, 2)}
+	for _, size := range []int{10, 1000} {
+		claim.messages <- &sarama.ConsumerMessage{
+			Value: []byte(strings.Repeat("a", size)),
+			Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce_specversion"), Value: []byte("1.0")},
+				{Key: []byte("ce_id"), Value: []byte("id")},
+				{Key: []byte("ce_type"), Value: []byte("type")},
+				{Key: []byte("ce_source"), Value: []byte("source")},
+				{Key: []byte("content-type"), Value: []byte("text/plain")},
+			},
+		}
+	}
+	close(claim.messages)
+	go func() {
+		_ = r.ConsumeClaim(session, claim)
+	}()
+
+	ctx := context.Background()
+	for _, size := range []int{10, 1000} {
+		m, err := r.Receive(ctx)
+		require.NoError(t, err)
+		_, err = binding.ToEvent(ctx, m)
+		if size < 100 {
+			require.NoError(t, err)
+			require.NoError(t, m.Finish(nil))
+		} else {
+			require.True(t, errors.Is(err, binding.ErrLimitExceeded), "unexpected error %v", err)
+			require.NoError(t, m.Finish(err))
+		}
+	}
+	// Only the message within the limits is marked, the other one is passed to the dead letter func
+	require.Len(t, session.marked, 1)
+	require.Len(t, session.marked[0].Value, 10)
+	require.Len(t, deadLetters, 1)
+	require.Len(t, deadLetters[0].Value, 1000)
+}
@@ -87,7 +87,9 @@ func (s *Sender) Close(ctx context.Context) error {
 
 type withMessageKey struct{}
 
-// WithMessageKey allows to set the key used when sending the producer message
+// WithMessageKey allows to set the key used when sending the producer message.
+// If the message carries the partitionkey extension, the extension value takes precedence,
+// unless the key mapping is disabled with WithSkipKeyMapping.
 func WithMessageKey(ctx context.Context, key sarama.Encoder) context.Context {
 	return context.WithValue(ctx, withMessageKey{}, key)
 }
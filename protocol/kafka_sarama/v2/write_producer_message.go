@@ -15,13 +15,10 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
-const (
-	partitionKey = "partitionkey"
-)
-
 // WriteProducerMessage fills the provided producerMessage with the message m.
 // Using context you can tweak the encoding processing (more details on binding.Write documentation).
 // By default, this function implements the key mapping, trying to set the key of the message based on partitionKey extension.
@@ -31,21 +28,11 @@ func WriteProducerMessage(ctx context.Context, m binding.Message, producerMessag
 
 	skipKey := binding.GetOrDefaultFromCtx(ctx, skipKeyKey{}, false).(bool)
 
-	var key string
+	var pe extensions.PartitioningExtension
 
 	// If skipKey = false, then we add a transformer that extracts the key
 	if !skipKey {
-		transformers = append(transformers, binding.TransformerFunc(func(r binding.MessageMetadataReader, w binding.MessageMetadataWriter) error {
-			ext := r.GetExtension(partitionKey)
-			if !types.IsZero(ext) {
-				extStr, err := types.Format(ext)
-				if err != nil {
-					return err
-				}
-				key = extStr
-			}
-			return nil
-		}))
+		transformers = append(transformers, pe.ReadTransformer())
 	}
 
 	_, err := binding.Write(
@@ -55,8 +42,8 @@ func WriteProducerMessage(ctx context.Context, m binding.Message, producerMessag
 		writer,
 		transformers...,
 	)
-	if key != "" {
-		producerMessage.Key = sarama.StringEncoder(key)
+	if pe.PartitionKey != "" {
+		producerMessage.Key = sarama.StringEncoder(pe.PartitionKey)
 	}
 	return err
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	. "github.com/cloudevents/sdk-go/v2/binding/test"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	. "github.com/cloudevents/sdk-go/v2/test"
 )
 
@@ -158,7 +159,7 @@ func TestEncodeKafkaProducerMessage(t *testing.T) {
 
 				eventIn := ConvertEventExtensionsToString(t, e.Clone())
 				if tt.addPartitionKey {
-					eventIn.SetExtension(partitionKey, testKey)
+					eventIn.SetExtension(extensions.PartitionKeyExtension, testKey)
 				}
 				messageIn := tt.messageFactory(eventIn)
 
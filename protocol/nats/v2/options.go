@@ -9,6 +9,8 @@ import (
 	"errors"
 
 	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
 )
 
 var ErrInvalidQueueName = errors.New("invalid queue name for QueueSubscriber")
@@ -38,6 +40,16 @@ func WithSenderOptions(opts ...SenderOption) ProtocolOption {
 
 type SenderOption func(*Sender) error
 
+// WithPartitionKeyHeader makes the Sender send the partitionkey extension of the events as the
+// PartitionKeyHeader header too, so it can be read without parsing the event.
+// The NATS server must support headers, otherwise the events with a partition key can't be sent.
+func WithPartitionKeyHeader() SenderOption {
+	return func(s *Sender) error {
+		s.partitionKeyHeader = true
+		return nil
+	}
+}
+
 type ConsumerOption func(*Consumer) error
 
 // WithQueueSubscriber configures the Consumer to join a queue group when subscribing
@@ -50,3 +62,12 @@ func WithQueueSubscriber(queue string) ConsumerOption {
 		return nil
 	}
 }
+
+// WithConsumerLimits bounds the size, the number of extensions and the length of the extension values
+// of the received events. Reading a message exceeding the limits fails, so the message is NACKed.
+func WithConsumerLimits(limits binding.Limits) ConsumerOption {
+	return func(c *Consumer) error {
+		c.limits = &limits
+		return nil
+	}
+}
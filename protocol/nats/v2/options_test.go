@@ -6,8 +6,16 @@
 package nats
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
 )
 
 func TestWithQueueSubscriber(t *testing.T) {
@@ -62,3 +70,37 @@ func TestWithQueueSubscriber(t *testing.T) {
 		})
 	}
 }
+
+func TestWithPartitionKeyHeader(t *testing.T) {
+	s := &Sender{}
+	if err := s.applyOptions(WithPartitionKeyHeader()); err != nil {
+		t.Errorf("applyOptions(WithPartitionKeyHeader()) = %v, want nil", err)
+	}
+	if !s.partitionKeyHeader {
+		t.Errorf("partitionKeyHeader = false, want true")
+	}
+}
+
+func TestWithConsumerLimits(t *testing.T) {
+	c, err := NewConsumerFromConn(nil, "subject", WithConsumerLimits(binding.Limits{MaxEventSize: 100}))
+	if err != nil {
+		t.Fatalf("NewConsumerFromConn() = %v, want nil", err)
+	}
+
+	for _, size := range []int{10, 1000} {
+		data := fmt.Sprintf(`{"specversion":"1.0","id":"id","type":"type","source":"source","data":%q}`, strings.Repeat("a", size))
+		go c.MsgHandler(&nats.Msg{Subject: "subject", Data: []byte(data)})
+
+		m, err := c.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("Receive() = %v, want nil", err)
+		}
+		_, err = binding.ToEvent(context.Background(), m)
+		if size < 100 && err != nil {
+			t.Errorf("ToEvent() = %v, want nil", err)
+		}
+		if size > 100 && !errors.Is(err, binding.ErrLimitExceeded) {
+			t.Errorf("ToEvent() = %v, want %v", err, binding.ErrLimitExceeded)
+		}
+	}
+}
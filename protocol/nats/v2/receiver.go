@@ -22,6 +22,7 @@ type msgErr struct {
 
 type Receiver struct {
 	incoming chan msgErr
+	limits   *binding.Limits
 }
 
 func NewReceiver() *Receiver {
@@ -33,6 +34,10 @@ func NewReceiver() *Receiver {
 // MsgHandler implements nats.MsgHandler and publishes messages onto our internal incoming channel to be delivered
 // via r.Receive(ctx)
 func (r *Receiver) MsgHandler(msg *nats.Msg) {
+	if r.limits != nil {
+		r.incoming <- msgErr{msg: binding.WithLimits(NewMessage(msg), *r.limits)}
+		return
+	}
 	r.incoming <- msgErr{msg: NewMessage(msg)}
 }
 
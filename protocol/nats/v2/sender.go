@@ -10,16 +10,22 @@ import (
 	"context"
 	"fmt"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 
 	"github.com/nats-io/nats.go"
 )
 
+// PartitionKeyHeader is the NATS header carrying the partitionkey extension of the sent event
+const PartitionKeyHeader = "ce-partitionkey"
+
 type Sender struct {
 	Conn    *nats.Conn
 	Subject string
 
 	connOwned bool
+	// partitionKeyHeader sends the partitionkey extension as PartitionKeyHeader, see WithPartitionKeyHeader
+	partitionKeyHeader bool
 }
 
 // NewSender creates a new protocol.Sender responsible for opening and closing the STAN connection
@@ -67,11 +73,24 @@ func (s *Sender) Send(ctx context.Context, in binding.Message, transformers ...b
 		}
 	}()
 
+	var pe extensions.PartitioningExtension
+	if s.partitionKeyHeader {
+		transformers = append(transformers, pe.ReadTransformer())
+	}
+
 	writer := new(bytes.Buffer)
 	if err = WriteMsg(ctx, in, writer, transformers...); err != nil {
 		return err
 	}
-	return s.Conn.Publish(s.Subject, writer.Bytes())
+	if pe.PartitionKey == "" {
+		return s.Conn.Publish(s.Subject, writer.Bytes())
+	}
+
+	// Expose the partition key as header, so it can be used without parsing the event
+	msg := nats.NewMsg(s.Subject)
+	msg.Data = writer.Bytes()
+	msg.Header.Set(PartitionKeyHeader, pe.PartitionKey)
+	return s.Conn.PublishMsg(msg)
 }
 
 // Close implements Closer.Close
@@ -5,10 +5,10 @@ go 1.14
 replace github.com/cloudevents/sdk-go/v2 => ../../../v2
 
 require (
-	cloud.google.com/go/pubsub v1.3.1
+	cloud.google.com/go/pubsub v1.4.0
 	github.com/cloudevents/sdk-go/v2 v2.5.0
 	github.com/google/go-cmp v0.5.0
 	golang.org/x/sync v0.0.0-20200317015054-43a5402ce75a
-	google.golang.org/api v0.24.0
+	google.golang.org/api v0.25.0
 	google.golang.org/grpc v1.29.1
 )
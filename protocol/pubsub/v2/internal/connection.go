@@ -70,6 +70,11 @@ type Connection struct {
 	// Default is 25 hours.
 	// This can only be set prior to first call of any function.
 	RetentionDuration *time.Duration
+
+	// MessageOrdering enables the ordered publishing of the messages with an
+	// ordering key. When false, the ordering keys are not published.
+	// This can only be set prior to first call of any function.
+	MessageOrdering bool
 }
 
 const (
@@ -119,6 +124,8 @@ func (c *Connection) getOrCreateTopicInfo(ctx context.Context, getAlreadyOpenOnl
 			}
 			ti.wasCreated = true
 		}
+		// Messages without an ordering key are still published concurrently.
+		topic.EnableMessageOrdering = c.MessageOrdering
 		// Success.
 		ti.topic = topic
 	})
@@ -292,8 +299,18 @@ func (c *Connection) Publish(ctx context.Context, msg *pubsub.Message) (*binding
 		return nil, err
 	}
 
+	if !c.MessageOrdering && msg.OrderingKey != "" {
+		// The topic rejects the ordering keys when ordering is disabled. msg belongs to the caller, so
+		// the key is removed from a copy.
+		unordered := *msg
+		unordered.OrderingKey = ""
+		msg = &unordered
+	}
 	r := topic.Publish(ctx, msg)
-	_, err = r.Get(ctx)
+	if _, err = r.Get(ctx); err != nil && msg.OrderingKey != "" {
+		// The topic stops publishing the ordering key after a failure, until resumed
+		topic.ResumePublish(msg.OrderingKey)
+	}
 	return nil, err
 }
 
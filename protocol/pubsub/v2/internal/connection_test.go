@@ -256,6 +256,76 @@ func TestPublishAfterPublishFailure(t *testing.T) {
 	}
 }
 
+// Make sure that publishing an ordering key works after a failed publish of the same key
+func TestPublishOrderingKeyAfterPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	pc := &testPubsubClient{}
+	defer pc.Close()
+
+	projectID, topicID, subID := "test-project", "test-topic", "test-sub"
+
+	failureMap := make(map[string][]failPattern)
+	failureMap["/google.pubsub.v1.Publisher/Publish"] = []failPattern{{
+		ErrReturn: fmt.Errorf("Injected error"),
+		Count:     1,
+		Delay:     0}}
+	client, err := pc.New(ctx, projectID, failureMap)
+	if err != nil {
+		t.Fatalf("failed to create pubsub client: %v", err)
+	}
+	defer client.Close()
+
+	psconn := &Connection{
+		AllowCreateSubscription: true,
+		AllowCreateTopic:        true,
+		MessageOrdering:         true,
+		Client:                  client,
+		ProjectID:               projectID,
+		TopicID:                 topicID,
+		SubscriptionID:          subID,
+	}
+
+	// Fails due to injected failure
+	if _, err := psconn.Publish(ctx, &pubsub.Message{Data: []byte("msg-data-1"), OrderingKey: "key"}); err == nil {
+		t.Errorf("Expected publish failure, but didn't see it: %v", err)
+	}
+	// Succeeds
+	if _, err := psconn.Publish(ctx, &pubsub.Message{Data: []byte("msg-data-1"), OrderingKey: "key"}); err != nil {
+		t.Errorf("failed to publish message: %v", err)
+	}
+}
+
+// Make sure that the ordering keys are dropped when message ordering isn't enabled
+func TestPublishOrderingKeyWithoutMessageOrdering(t *testing.T) {
+	ctx := context.Background()
+	pc := &testPubsubClient{}
+	defer pc.Close()
+
+	projectID, topicID, subID := "test-project", "test-topic", "test-sub"
+
+	client, err := pc.New(ctx, projectID, nil)
+	if err != nil {
+		t.Fatalf("failed to create pubsub client: %v", err)
+	}
+	defer client.Close()
+
+	psconn := &Connection{
+		AllowCreateTopic: true,
+		Client:           client,
+		ProjectID:        projectID,
+		TopicID:          topicID,
+		SubscriptionID:   subID,
+	}
+
+	msg := &pubsub.Message{Data: []byte("msg-data-1"), OrderingKey: "key"}
+	if _, err := psconn.Publish(ctx, msg); err != nil {
+		t.Errorf("failed to publish message: %v", err)
+	}
+	if msg.OrderingKey != "key" {
+		t.Errorf("the message of the caller was modified, OrderingKey: %q", msg.OrderingKey)
+	}
+}
+
 // Test Publishing after Deleting a first version of a topic
 func TestPublishCreateTopicAfterDelete(t *testing.T) {
 	ctx := context.Background()
@@ -14,6 +14,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 )
 
 const (
@@ -100,6 +101,12 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 		}
 	}
 
+	if _, ok := m.internal.Attributes[prefix+extensions.PartitionKeyExtension]; !ok && m.internal.OrderingKey != "" {
+		if err = encoder.SetExtension(extensions.PartitionKeyExtension, m.internal.OrderingKey); err != nil {
+			return err
+		}
+	}
+
 	if m.internal.Data != nil {
 		return encoder.SetData(bytes.NewBuffer(m.internal.Data))
 	}
@@ -116,7 +123,13 @@ func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
 }
 
 func (m *Message) GetExtension(name string) interface{} {
-	return m.internal.Attributes[prefix+name]
+	if v, ok := m.internal.Attributes[prefix+name]; ok {
+		return v
+	}
+	if name == extensions.PartitionKeyExtension {
+		return m.internal.OrderingKey
+	}
+	return ""
 }
 
 // Finish marks the message to be forgotten.
@@ -12,6 +12,7 @@ import (
 	"cloud.google.com/go/pubsub"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 )
 
 func TestReadStructured(t *testing.T) {
@@ -45,3 +46,29 @@ func TestReadStructured(t *testing.T) {
 		})
 	}
 }
+
+func TestPartitionKeyOrderingKey(t *testing.T) {
+	e := event.New()
+	e.SetID("testid")
+	e.SetSource("testsource")
+	e.SetType("testtype")
+	e.SetExtension(extensions.PartitionKeyExtension, "customer-42")
+
+	pm := &pubsub.Message{}
+	if err := WritePubSubMessage(context.Background(), binding.ToMessage(&e), pm); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pm.OrderingKey != "customer-42" {
+		t.Errorf("OrderingKey unexpected. got: %q, want: %q", pm.OrderingKey, "customer-42")
+	}
+
+	// A message published by a non CloudEvents producer only carries the ordering key
+	delete(pm.Attributes, prefix+extensions.PartitionKeyExtension)
+	got, err := binding.ToEvent(context.Background(), NewMessage(pm))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pe, ok := extensions.GetPartitioningExtension(*got); !ok || pe.PartitionKey != "customer-42" {
+		t.Errorf("partitionkey unexpected. got: %q, want: %q", pe.PartitionKey, "customer-42")
+	}
+}
@@ -159,3 +159,14 @@ func WithReceiveSettings(rs *pubsub.ReceiveSettings) Option {
 		return nil
 	}
 }
+
+// WithMessageOrdering enables the message ordering of the topics, publishing the
+// events with the same partitionkey extension in order, with the partition key as
+// ordering key. The subscriptions must enable message ordering to receive them in order.
+// Without this option, the partitionkey extension is not used as ordering key.
+func WithMessageOrdering() Option {
+	return func(t *Protocol) error {
+		t.MessageOrdering = true
+		return nil
+	}
+}
@@ -43,6 +43,10 @@ type Protocol struct {
 	// subscription if it does not exist.
 	AllowCreateSubscription bool
 
+	// MessageOrdering enables the ordered publishing of the events with the
+	// same partitionkey extension, see WithMessageOrdering.
+	MessageOrdering bool
+
 	projectID string
 	topicID   string
 
@@ -145,6 +149,7 @@ func (t *Protocol) getOrCreateConnection(ctx context.Context, topic, subscriptio
 		AllowCreateSubscription: t.AllowCreateSubscription,
 		AllowCreateTopic:        t.AllowCreateTopic,
 		ReceiveSettings:         t.ReceiveSettings,
+		MessageOrdering:         t.MessageOrdering,
 		Client:                  t.client,
 		ProjectID:               t.projectID,
 		TopicID:                 topic,
@@ -14,15 +14,21 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
 // WritePubSubMessage fills the provided pubsubMessage with the message m.
 // Using context you can tweak the encoding processing (more details on binding.Write documentation).
+// The partitionkey extension, if present, is used as the ordering key of the pubsubMessage.
+// The Protocol only publishes the ordering key with WithMessageOrdering.
 func WritePubSubMessage(ctx context.Context, m binding.Message, pubsubMessage *pubsub.Message, transformers ...binding.Transformer) error {
 	structuredWriter := (*pubsubMessagePublisher)(pubsubMessage)
 	binaryWriter := (*pubsubMessagePublisher)(pubsubMessage)
 
+	var pe extensions.PartitioningExtension
+	transformers = append(transformers, pe.ReadTransformer())
+
 	_, err := binding.Write(
 		ctx,
 		m,
@@ -30,7 +36,11 @@ func WritePubSubMessage(ctx context.Context, m binding.Message, pubsubMessage *p
 		binaryWriter,
 		transformers...,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	pubsubMessage.OrderingKey = pe.PartitionKey
+	return nil
 }
 
 type pubsubMessagePublisher pubsub.Message
@@ -0,0 +1,172 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/utils"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// DefaultReconnectDelay is the default delay before reconnecting to the event stream
+const DefaultReconnectDelay = 3 * time.Second
+
+// Client implements protocol.Receiver and protocol.Closer, receiving the events of an event stream.
+// It connects on the first call to Receive, and reconnects with the Last-Event-ID header when the
+// connection is lost, to resume the stream after the last received event.
+type Client struct {
+	url            string
+	httpClient     *http.Client
+	filter         string
+	reconnectDelay time.Duration
+	lastEventID    string
+
+	startOnce sync.Once
+	cancel    context.CancelFunc
+	incoming  chan []byte
+	done      chan struct{}
+}
+
+// NewClient creates a Client receiving the events of the event stream at url.
+func NewClient(url string, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		url:            url,
+		httpClient:     http.DefaultClient,
+		reconnectDelay: DefaultReconnectDelay,
+		incoming:       make(chan []byte),
+		done:           make(chan struct{}),
+	}
+	for _, fn := range opts {
+		if err := fn(c); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := c.streamURL(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) streamURL() (string, error) {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return "", fmt.Errorf("invalid sse url: %w", err)
+	}
+	if c.filter != "" {
+		query := u.Query()
+		query.Set(FilterParameter, c.filter)
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
+// Receive implements protocol.Receiver. It returns io.EOF after the Client is closed.
+func (c *Client) Receive(ctx context.Context) (binding.Message, error) {
+	c.startOnce.Do(func() {
+		var streamCtx context.Context
+		streamCtx, c.cancel = context.WithCancel(cecontext.WithLogger(context.Background(), cecontext.LoggerFrom(ctx)))
+		go c.stream(streamCtx)
+	})
+
+	select {
+	case data := <-c.incoming:
+		return utils.NewStructuredMessage(format.JSON, bytes.NewReader(data)), nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// stream reads the event stream, reconnecting until ctx is done
+func (c *Client) stream(ctx context.Context) {
+	defer close(c.done)
+	for {
+		if err := c.read(ctx); err != nil && ctx.Err() == nil {
+			cecontext.LoggerFrom(ctx).Debugw("sse stream interrupted, reconnecting", zap.Error(err), zap.Duration("delay", c.reconnectDelay))
+		}
+
+		timer := time.NewTimer(c.reconnectDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// read connects to the event stream and reads it until the connection is lost
+func (c *Client) read(ctx context.Context) error {
+	u, err := c.streamURL()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", ContentType)
+	req.Header.Set("Cache-Control", "no-cache")
+	if c.lastEventID != "" {
+		req.Header.Set(LastEventID, c.lastEventID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sse stream returned status code %d: %s", resp.StatusCode, body)
+	}
+
+	reader := newMessageReader(resp.Body, c.lastEventID)
+	for {
+		msg, err := reader.next()
+		if reader.retry > 0 {
+			c.reconnectDelay = reader.retry
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case c.incoming <- msg.data:
+			c.lastEventID = msg.id
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close implements protocol.Closer, closing the connection to the event stream.
+func (c *Client) Close(ctx context.Context) error {
+	c.startOnce.Do(func() {
+		c.cancel = func() {}
+		close(c.done)
+	})
+	c.cancel()
+	return nil
+}
+
+var _ protocol.Receiver = (*Client)(nil)
+var _ protocol.Closer = (*Client)(nil)
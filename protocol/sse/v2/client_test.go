@@ -0,0 +1,105 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func requireReceived(t *testing.T, c *Client, ids ...string) {
+	for _, id := range ids {
+		m, err := c.Receive(context.Background())
+		require.NoError(t, err)
+		e, err := binding.ToEvent(context.Background(), m)
+		require.NoError(t, err)
+		AssertEventEquals(t, testEvent(id, e.Subject()), *e)
+	}
+}
+
+// waitClients waits until n clients are connected to s
+func waitClients(t *testing.T, s *Server, n int) {
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.clients) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestClient(t *testing.T) {
+	s, server := newTestServer(t)
+	c, err := NewClient(server.URL, WithFilter("subject = 'subject-1'"), WithReconnectDelay(10*time.Millisecond))
+	require.NoError(t, err)
+
+	received := make(chan error, 1)
+	go func() {
+		_, err := c.Receive(context.Background())
+		received <- err
+	}()
+	waitClients(t, s, 1)
+	sendEvents(t, s, 1, 3)
+	require.NoError(t, <-received)
+	requireReceived(t, c, "3")
+
+	// The client reconnects after the last received event
+	s.mu.Lock()
+	for client := range s.clients {
+		s.removeClient(client)
+	}
+	s.mu.Unlock()
+	sendEvents(t, s, 4, 5)
+	requireReceived(t, c, "5")
+
+	require.NoError(t, c.Close(context.Background()))
+	_, err = c.Receive(context.Background())
+	require.Equal(t, io.EOF, err)
+	waitClients(t, s, 0)
+}
+
+func TestClient_lastEventID(t *testing.T) {
+	s, server := newTestServer(t)
+	sendEvents(t, s, 1, 3)
+
+	c, err := NewClient(server.URL, WithLastEventID("1"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close(context.Background()) })
+	requireReceived(t, c, "2", "3")
+}
+
+func TestClient_cancel(t *testing.T) {
+	c, err := NewClient("http://localhost:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.Receive(ctx)
+	require.Equal(t, io.EOF, err)
+	require.NoError(t, c.Close(context.Background()))
+}
+
+func TestMessageReader(t *testing.T) {
+	r := newMessageReader(strings.NewReader(": comment\r\nretry: 100\nid: 1\ndata: a\ndata:b\nevent: e\n\nid\n\ndata: c\n\ndata: incomplete\n"), "0")
+
+	msg, err := r.next()
+	require.NoError(t, err)
+	require.Equal(t, &message{id: "1", data: []byte("a\nb")}, msg)
+	require.Equal(t, 100*time.Millisecond, r.retry)
+
+	// An empty id resets the last event id
+	msg, err = r.next()
+	require.NoError(t, err)
+	require.Equal(t, &message{id: "", data: []byte("c")}, msg)
+
+	_, err = r.next()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,14 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package sse implements the Server-Sent Events protocol binding, to push events to the browsers:
+https://html.spec.whatwg.org/multipage/server-sent-events.html
+
+Each event is written as one SSE message holding the event in the structured JSON format, with the id of the event
+as the SSE id. Server is a protocol.Sender serving the connected clients on an http.Handler, and Client is a
+protocol.Receiver consuming the events from Go.
+*/
+package v2
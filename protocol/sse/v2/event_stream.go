@@ -0,0 +1,102 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of the event streams
+const ContentType = "text/event-stream"
+
+// LastEventID is the header sent by the reconnecting clients with the id of the last received event
+const LastEventID = "Last-Event-ID"
+
+// encodeMessage returns the SSE message holding data, one data line per line of data
+func encodeMessage(id string, data []byte) []byte {
+	var b bytes.Buffer
+	// The line breaks and the NUL can't be sent in the id, such ids are dropped
+	if id != "" && !strings.ContainsAny(id, "\r\n\x00") {
+		b.WriteString("id: ")
+		b.WriteString(id)
+		b.WriteByte('\n')
+	}
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		b.WriteString("data: ")
+		b.Write(bytes.TrimSuffix(line, []byte{'\r'}))
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// message is a message read from an event stream
+type message struct {
+	id   string
+	data []byte
+}
+
+// messageReader reads the messages of an event stream
+type messageReader struct {
+	reader *bufio.Reader
+	// lastEventID is the id of the last message, kept by the following messages without id
+	lastEventID string
+	// retry is the reconnection delay requested by the server, if any
+	retry time.Duration
+}
+
+func newMessageReader(r io.Reader, lastEventID string) *messageReader {
+	return &messageReader{reader: bufio.NewReader(r), lastEventID: lastEventID}
+}
+
+// next returns the next message with data, skipping the comments and the unknown fields
+func (r *messageReader) next() (*message, error) {
+	var data bytes.Buffer
+	hasData := false
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil {
+			// An incomplete message is discarded
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if line == "" {
+			if !hasData {
+				continue
+			}
+			return &message{id: r.lastEventID, data: bytes.TrimSuffix(data.Bytes(), []byte{'\n'})}, nil
+		}
+		if line[0] == ':' {
+			// Comment
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
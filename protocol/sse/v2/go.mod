@@ -0,0 +1,14 @@
+module github.com/cloudevents/sdk-go/protocol/sse/v2
+
+go 1.14
+
+replace github.com/cloudevents/sdk-go/v2 => ../../../v2
+
+replace github.com/cloudevents/sdk-go/sql/v2 => ../../../sql/v2
+
+require (
+	github.com/cloudevents/sdk-go/sql/v2 v2.5.0
+	github.com/cloudevents/sdk-go/v2 v2.5.0
+	github.com/stretchr/testify v1.5.1
+	go.uber.org/zap v1.10.0
+)
@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Option is the function signature required to be considered an sse.Option.
+type Option func(*Server) error
+
+// WithReplayBufferSize sets the number of events kept to be sent again to the clients reconnecting with the
+// Last-Event-ID header. Zero disables the replay.
+func WithReplayBufferSize(size int) Option {
+	return func(s *Server) error {
+		if s == nil {
+			return fmt.Errorf("sse replay buffer size option can not set nil server")
+		}
+		if size < 0 {
+			return fmt.Errorf("sse replay buffer size option was given a negative size: %d", size)
+		}
+		s.replayBufferSize = size
+		return nil
+	}
+}
+
+// WithClientBufferSize sets the number of events queued for each client. A client is disconnected when its queue
+// is full.
+func WithClientBufferSize(size int) Option {
+	return func(s *Server) error {
+		if s == nil {
+			return fmt.Errorf("sse client buffer size option can not set nil server")
+		}
+		if size < 1 {
+			return fmt.Errorf("sse client buffer size option was given an invalid size: %d", size)
+		}
+		s.clientBufferSize = size
+		return nil
+	}
+}
+
+// WithKeepAlive sets the period of the comments sent to keep the idle connections open. Zero disables them.
+func WithKeepAlive(period time.Duration) Option {
+	return func(s *Server) error {
+		if s == nil {
+			return fmt.Errorf("sse keep alive option can not set nil server")
+		}
+		if period < 0 {
+			return fmt.Errorf("sse keep alive option was given a negative period: %v", period)
+		}
+		s.keepAlive = period
+		return nil
+	}
+}
+
+// ClientOption is the function signature required to be considered an sse.ClientOption.
+type ClientOption func(*Client) error
+
+// WithHTTPClient sets the HTTP client connecting to the event stream.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) error {
+		if c == nil {
+			return fmt.Errorf("sse http client option can not set nil client")
+		}
+		if client == nil {
+			return fmt.Errorf("sse http client option was given a nil http client")
+		}
+		c.httpClient = client
+		return nil
+	}
+}
+
+// WithFilter sets the CESQL expression filtering the events sent by the server.
+func WithFilter(expression string) ClientOption {
+	return func(c *Client) error {
+		if c == nil {
+			return fmt.Errorf("sse filter option can not set nil client")
+		}
+		c.filter = expression
+		return nil
+	}
+}
+
+// WithReconnectDelay sets the delay before reconnecting, until the server requests another one.
+func WithReconnectDelay(delay time.Duration) ClientOption {
+	return func(c *Client) error {
+		if c == nil {
+			return fmt.Errorf("sse reconnect delay option can not set nil client")
+		}
+		if delay < 0 {
+			return fmt.Errorf("sse reconnect delay option was given a negative delay: %v", delay)
+		}
+		c.reconnectDelay = delay
+		return nil
+	}
+}
+
+// WithLastEventID sets the id of the last event received, to resume the stream after it.
+func WithLastEventID(id string) ClientOption {
+	return func(c *Client) error {
+		if c == nil {
+			return fmt.Errorf("sse last event id option can not set nil client")
+		}
+		c.lastEventID = id
+		return nil
+	}
+}
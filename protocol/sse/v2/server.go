@@ -0,0 +1,286 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	// DefaultReplayBufferSize is the default number of events kept to be sent again to the reconnecting clients
+	DefaultReplayBufferSize = 100
+	// DefaultClientBufferSize is the default number of events queued for a client before it's evicted as a slow consumer
+	DefaultClientBufferSize = 32
+	// DefaultKeepAlive is the default period of the comments sent to keep the idle connections open
+	DefaultKeepAlive = 30 * time.Second
+
+	// FilterParameter is the query parameter of the CESQL expression filtering the events sent to a client
+	FilterParameter = "filter"
+)
+
+// ErrServerClosed is returned by Send after the Server is closed
+var ErrServerClosed = errors.New("sse: server closed")
+
+// Server implements protocol.Sender and protocol.Closer, sending the events to the clients connected to its
+// http.Handler. The last events are kept in a bounded replay buffer, and sent again to the clients reconnecting
+// with the Last-Event-ID header. Each client can filter the events with a CESQL expression in the "filter" query
+// parameter. A client too slow to consume its events is disconnected, and can reconnect to get the events it missed
+// from the replay buffer.
+type Server struct {
+	replayBufferSize int
+	clientBufferSize int
+	keepAlive        time.Duration
+
+	mu      sync.Mutex
+	replay  []*sseEvent
+	clients map[*sseClient]struct{}
+	closed  bool
+}
+
+// sseEvent is an event sent to the clients
+type sseEvent struct {
+	event   *event.Event
+	message []byte
+}
+
+// sseClient is a connected client
+type sseClient struct {
+	filter cesql.Expression
+	// events is closed when the client is evicted or the server closed
+	events chan []byte
+}
+
+func (c *sseClient) matches(e *event.Event) bool {
+	if c.filter == nil {
+		return true
+	}
+	res, err := c.filter.Evaluate(*e)
+	return err == nil && res == true
+}
+
+// NewServer creates a Server. Serve its clients with the Server as http.Handler.
+func NewServer(opts ...Option) (*Server, error) {
+	s := &Server{
+		replayBufferSize: DefaultReplayBufferSize,
+		clientBufferSize: DefaultClientBufferSize,
+		keepAlive:        DefaultKeepAlive,
+		clients:          make(map[*sseClient]struct{}),
+	}
+	if err := s.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) applyOptions(opts ...Option) error {
+	for _, fn := range opts {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Send implements protocol.Sender, sending m to the connected clients matching it.
+func (s *Server) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+	defer func() { _ = m.Finish(err) }()
+
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	data, err := format.JSON.Marshal(e)
+	if err != nil {
+		return err
+	}
+	ev := &sseEvent{event: e, message: encodeMessage(e.ID(), data)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrServerClosed
+	}
+
+	if s.replayBufferSize > 0 {
+		if len(s.replay) == s.replayBufferSize {
+			s.replay[0] = nil
+			s.replay = s.replay[1:]
+		}
+		s.replay = append(s.replay, ev)
+	}
+
+	for c := range s.clients {
+		if !c.matches(e) {
+			continue
+		}
+		select {
+		case c.events <- ev.message:
+		default:
+			cecontext.LoggerFrom(ctx).Debug("evicting a slow sse client")
+			s.removeClient(c)
+		}
+	}
+	return nil
+}
+
+// removeClient disconnects c. s.mu must be held.
+func (s *Server) removeClient(c *sseClient) {
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+// Close implements protocol.Closer, disconnecting the clients.
+func (s *Server) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for c := range s.clients {
+		s.removeClient(c)
+	}
+	return nil
+}
+
+// connect registers a client, returning the events of the replay buffer after lastEventID.
+// If lastEventID isn't in the buffer anymore, all the events of the buffer are returned.
+func (s *Server) connect(filter cesql.Expression, lastEventID string) (*sseClient, [][]byte, error) {
+	c := &sseClient{filter: filter, events: make(chan []byte, s.clientBufferSize)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, nil, ErrServerClosed
+	}
+	s.clients[c] = struct{}{}
+
+	if lastEventID == "" {
+		return c, nil, nil
+	}
+	replay := s.replay
+	for i := len(replay) - 1; i >= 0; i-- {
+		if replay[i].event.ID() == lastEventID {
+			replay = replay[i+1:]
+			break
+		}
+	}
+	var messages [][]byte
+	for _, ev := range replay {
+		if c.matches(ev.event) {
+			messages = append(messages, ev.message)
+		}
+	}
+	return c, messages, nil
+}
+
+func (s *Server) disconnect(c *sseClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeClient(c)
+}
+
+// ServeHTTP implements http.Handler, streaming the events to the client until it disconnects.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	var filter cesql.Expression
+	if query := req.URL.Query().Get(FilterParameter); query != "" {
+		var err error
+		if filter, err = parseFilter(query); err != nil {
+			http.Error(rw, fmt.Sprintf("Invalid filter: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	c, replay, err := s.connect(filter, req.Header.Get(LastEventID))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer s.disconnect(c)
+
+	rw.Header().Set("Content-Type", ContentType)
+	rw.Header().Set("Cache-Control", "no-cache")
+	// Disable the buffering of the reverse proxies
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+	for _, message := range replay {
+		if _, err := rw.Write(message); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	var keepAlive <-chan time.Time
+	if s.keepAlive > 0 {
+		ticker := time.NewTicker(s.keepAlive)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case message, ok := <-c.events:
+			if !ok {
+				return
+			}
+			if _, err := rw.Write(message); err != nil {
+				cecontext.LoggerFrom(req.Context()).Debugw("cannot write to the sse client", zap.Error(err))
+				return
+			}
+		case <-keepAlive:
+			if _, err := rw.Write([]byte(":\n\n")); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// parseFilter parses the CESQL expression of a client filter. The parser panics on some incomplete expressions,
+// which are reported as errors.
+func parseFilter(query string) (filter cesql.Expression, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			filter, err = nil, fmt.Errorf("invalid expression %q", query)
+		}
+	}()
+	return cesqlparser.Parse(query)
+}
+
+var _ protocol.Sender = (*Server)(nil)
+var _ protocol.Closer = (*Server)(nil)
+var _ http.Handler = (*Server)(nil)
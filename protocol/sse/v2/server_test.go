@@ -0,0 +1,162 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func testEvent(id string, subject string) cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID(id)
+	e.SetType("type")
+	e.SetSource("source")
+	e.SetSubject(subject)
+	_ = e.SetData(cloudevents.ApplicationJSON, map[string]string{"hello": "world\nline"})
+	return e
+}
+
+func sendEvents(t *testing.T, s *Server, from, to int) {
+	for i := from; i <= to; i++ {
+		e := testEvent(strconv.Itoa(i), "subject-"+strconv.Itoa(i%2))
+		require.NoError(t, s.Send(context.Background(), binding.ToMessage(&e)))
+	}
+}
+
+func newTestServer(t *testing.T, opts ...Option) (*Server, *httptest.Server) {
+	s, err := NewServer(opts...)
+	require.NoError(t, err)
+	server := httptest.NewServer(s)
+	t.Cleanup(func() {
+		_ = s.Close(context.Background())
+		server.Close()
+	})
+	return s, server
+}
+
+// connectTestClient connects to the stream, returning once the client is registered
+func connectTestClient(t *testing.T, s *Server, server *httptest.Server, query url.Values, lastEventID string) *messageReader {
+	s.mu.Lock()
+	clients := len(s.clients)
+	s.mu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"?"+query.Encode(), nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set(LastEventID, lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.clients) > clients
+	}, time.Second, time.Millisecond)
+	return newMessageReader(resp.Body, "")
+}
+
+func requireMessages(t *testing.T, r *messageReader, ids ...string) {
+	for _, id := range ids {
+		msg, err := r.next()
+		require.NoError(t, err)
+		require.Equal(t, id, msg.id)
+
+		var e cloudevents.Event
+		require.NoError(t, format.JSON.Unmarshal(msg.data, &e))
+		AssertEventEquals(t, testEvent(id, e.Subject()), e)
+	}
+}
+
+func TestServer(t *testing.T) {
+	s, server := newTestServer(t)
+	r := connectTestClient(t, s, server, nil, "")
+
+	sendEvents(t, s, 1, 3)
+	requireMessages(t, r, "1", "2", "3")
+}
+
+func TestServer_replay(t *testing.T) {
+	s, server := newTestServer(t, WithReplayBufferSize(3))
+	sendEvents(t, s, 1, 5)
+
+	// The events after the last event id are sent again
+	r := connectTestClient(t, s, server, nil, "3")
+	sendEvents(t, s, 6, 6)
+	requireMessages(t, r, "4", "5", "6")
+
+	// The whole buffer is sent if the last event id isn't in the buffer anymore
+	r = connectTestClient(t, s, server, nil, "1")
+	requireMessages(t, r, "4", "5", "6")
+}
+
+func TestServer_filter(t *testing.T) {
+	s, server := newTestServer(t)
+	sendEvents(t, s, 1, 2)
+
+	r := connectTestClient(t, s, server, url.Values{FilterParameter: {"subject = 'subject-1'"}}, "1")
+	sendEvents(t, s, 3, 6)
+	requireMessages(t, r, "3", "5")
+
+	resp, err := http.Get(server.URL + "?" + url.Values{FilterParameter: {"subject ="}}.Encode())
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_slowConsumer(t *testing.T) {
+	s, err := NewServer(WithClientBufferSize(2))
+	require.NoError(t, err)
+	// A client not consuming its events
+	c, _, err := s.connect(nil, "")
+	require.NoError(t, err)
+
+	sendEvents(t, s, 1, 3)
+	s.mu.Lock()
+	require.Empty(t, s.clients)
+	s.mu.Unlock()
+
+	// The client gets the queued events, then it's disconnected
+	require.Len(t, c.events, 2)
+	<-c.events
+	<-c.events
+	_, ok := <-c.events
+	require.False(t, ok)
+}
+
+func TestServer_close(t *testing.T) {
+	s, server := newTestServer(t)
+	r := connectTestClient(t, s, server, nil, "")
+
+	require.NoError(t, s.Close(context.Background()))
+	_, err := r.next()
+	require.Equal(t, io.EOF, err)
+
+	e := testEvent("1", "")
+	require.Equal(t, ErrServerClosed, s.Send(context.Background(), binding.ToMessage(&e)))
+}
+
+func TestEncodeMessage(t *testing.T) {
+	require.Equal(t, "id: 1\ndata: a\ndata: b\n\n", string(encodeMessage("1", []byte("a\nb"))))
+	require.Equal(t, "data: {}\n\n", string(encodeMessage("a\nb", []byte("{}"))))
+}
@@ -7,6 +7,7 @@ package v2
 
 import (
 	"context"
+	"time"
 
 	"nhooyr.io/websocket"
 )
@@ -18,3 +19,12 @@ type reasonKey struct{}
 func WithCloseReason(ctx context.Context, code websocket.StatusCode, reason string) context.Context {
 	return context.WithValue(context.WithValue(ctx, codeKey{}, code), reasonKey{}, reason)
 }
+
+// detachedContext keeps the values of a connection context, without its cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
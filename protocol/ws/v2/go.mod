@@ -6,6 +6,7 @@ replace github.com/cloudevents/sdk-go/v2 => ../../../v2
 
 require (
 	github.com/cloudevents/sdk-go/v2 v2.5.0
+	github.com/google/uuid v1.1.1
 	github.com/stretchr/testify v1.5.1
 	nhooyr.io/websocket v1.8.6
 )
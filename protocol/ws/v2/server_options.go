@@ -0,0 +1,71 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// ServerOption is the function signature required to be considered a ws.ServerOption.
+type ServerOption func(*ServerProtocol) error
+
+// WithAcceptOptions sets the options accepting the connections, like the allowed origins.
+// The subprotocols are always the SupportedSubprotocols.
+func WithAcceptOptions(opts websocket.AcceptOptions) ServerOption {
+	return func(p *ServerProtocol) error {
+		if p == nil {
+			return fmt.Errorf("ws accept options option can not set nil protocol")
+		}
+		p.acceptOptions = opts
+		return nil
+	}
+}
+
+// WithPingInterval sets the period of the pings keeping the connections alive. A connection not answering
+// a ping within the interval is closed. 0 disables the pings.
+func WithPingInterval(interval time.Duration) ServerOption {
+	return func(p *ServerProtocol) error {
+		if p == nil {
+			return fmt.Errorf("ws ping interval option can not set nil protocol")
+		}
+		if interval < 0 {
+			return fmt.Errorf("ws ping interval option was given a negative interval: %s", interval)
+		}
+		p.pingInterval = interval
+		return nil
+	}
+}
+
+// WithConnectionBufferSize sets the number of messages queued for each connection, in each direction.
+func WithConnectionBufferSize(size int) ServerOption {
+	return func(p *ServerProtocol) error {
+		if p == nil {
+			return fmt.Errorf("ws connection buffer size option can not set nil protocol")
+		}
+		if size <= 0 {
+			return fmt.Errorf("ws connection buffer size option was given an invalid size: %d", size)
+		}
+		p.bufferSize = size
+		return nil
+	}
+}
+
+// WithReadLimit sets the maximum size of the messages read from the connections, see websocket.Conn.SetReadLimit.
+func WithReadLimit(limit int64) ServerOption {
+	return func(p *ServerProtocol) error {
+		if p == nil {
+			return fmt.Errorf("ws read limit option can not set nil protocol")
+		}
+		if limit <= 0 {
+			return fmt.Errorf("ws read limit option was given an invalid limit: %d", limit)
+		}
+		p.readLimit = limit
+		return nil
+	}
+}
@@ -0,0 +1,447 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"nhooyr.io/websocket"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/utils"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	// DefaultPingInterval is the default period of the pings sent to keep the connections alive
+	DefaultPingInterval = 30 * time.Second
+	// DefaultConnectionBufferSize is the default number of messages queued for each connection, in each direction
+	DefaultConnectionBufferSize = 16
+)
+
+var (
+	// ErrServerClosed is returned by the ServerProtocol after it's closed
+	ErrServerClosed = errors.New("ws: server closed")
+	// ErrConnectionNotFound is returned by SendTo when there is no open connection with the given id
+	ErrConnectionNotFound = errors.New("ws: connection not found")
+)
+
+type connectionKey struct{}
+
+// ConnectionFrom returns the connection which received the event, or nil if the event wasn't received
+// by a ServerProtocol.
+func ConnectionFrom(ctx context.Context) *Connection {
+	c, _ := ctx.Value(connectionKey{}).(*Connection)
+	return c
+}
+
+// Connection is a WebSocket connection accepted by a ServerProtocol.
+type Connection struct {
+	// ID identifies the connection, see ServerProtocol.SendTo
+	ID string
+	// Request is the HTTP request which opened the connection
+	Request *http.Request
+	// Subprotocol is the negotiated subprotocol
+	Subprotocol string
+	// ConnectedAt is the time the connection was accepted
+	ConnectedAt time.Time
+
+	conn        *websocket.Conn
+	format      format.Format
+	messageType websocket.MessageType
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// inbound and outbound queue the messages received and sent on the connection
+	inbound  chan []byte
+	outbound chan []byte
+	// blocked is 1 while reading the connection is paused because inbound is full
+	blocked int32
+
+	closeOnce sync.Once
+}
+
+// close closes the connection with code and reason. It's safe to call it multiple times.
+func (c *Connection) close(code websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		_ = c.conn.Close(code, reason)
+		c.cancel()
+	})
+}
+
+// abort closes the connection without waiting for its pending write: a write blocked by a peer not reading
+// holds the connection, so it's canceled first, and the close frame is sent only if it can still be.
+func (c *Connection) abort(code websocket.StatusCode, reason string) {
+	c.cancel()
+	c.close(code, reason)
+}
+
+// serverMessage is a message received on a connection, carrying the values of the context of the connection.
+// The context isn't canceled when the connection is closed, since the message can still be delivered after.
+type serverMessage struct {
+	binding.Message
+	ctx context.Context
+}
+
+func (m *serverMessage) Context() context.Context {
+	return m.ctx
+}
+
+var _ binding.MessageContext = (*serverMessage)(nil)
+
+// ServerProtocol implements protocol.Receiver, protocol.Sender and protocol.Closer for the WebSocket
+// connections accepted by its http.Handler. It negotiates the cloudevents.* subprotocols, and receives
+// the events of all the connections. Send broadcasts an event to all the connections, SendTo sends it to
+// a single connection.
+//
+// Each connection has bounded queues of DefaultConnectionBufferSize messages: when the received messages
+// aren't consumed, the reading of the connection is paused, and a connection too slow to consume the
+// broadcast events is closed. The connections are kept alive with pings, see WithPingInterval.
+type ServerProtocol struct {
+	acceptOptions websocket.AcceptOptions
+	pingInterval  time.Duration
+	bufferSize    int
+	readLimit     int64
+
+	incoming chan binding.Message
+	done     chan struct{}
+
+	mu     sync.Mutex
+	conns  map[string]*Connection
+	closed bool
+}
+
+// NewServerProtocol creates a ServerProtocol. Accept the connections with the ServerProtocol as http.Handler.
+func NewServerProtocol(opts ...ServerOption) (*ServerProtocol, error) {
+	p := &ServerProtocol{
+		pingInterval: DefaultPingInterval,
+		bufferSize:   DefaultConnectionBufferSize,
+		incoming:     make(chan binding.Message),
+		done:         make(chan struct{}),
+		conns:        make(map[string]*Connection),
+	}
+	if err := p.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+	p.acceptOptions.Subprotocols = SupportedSubprotocols
+	return p, nil
+}
+
+func (p *ServerProtocol) applyOptions(opts ...ServerOption) error {
+	for _, fn := range opts {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Receive implements protocol.Receiver, returning the next event received on any connection.
+// The context of the returned message carries its Connection, see ConnectionFrom.
+func (p *ServerProtocol) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m := <-p.incoming:
+		return m, nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	case <-p.done:
+		return nil, io.EOF
+	}
+}
+
+// Send implements protocol.Sender, broadcasting m to all the open connections. The connections whose
+// outbound queue is full are closed.
+func (p *ServerProtocol) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+	defer func() { _ = m.Finish(err) }()
+
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrServerClosed
+	}
+	encoded := make(map[format.Format][]byte)
+	for _, c := range p.conns {
+		b, ok := encoded[c.format]
+		if !ok {
+			if b, err = c.format.Marshal(e); err != nil {
+				return err
+			}
+			encoded[c.format] = b
+		}
+		select {
+		case c.outbound <- b:
+		default:
+			cecontext.LoggerFrom(ctx).Debugw("closing a slow ws connection", "id", c.ID)
+			go c.abort(websocket.StatusTryAgainLater, "slow consumer")
+		}
+	}
+	return nil
+}
+
+// SendTo sends m to the connection with the given id, waiting for room in its outbound queue.
+// Returns ErrConnectionNotFound if there is no open connection with this id.
+func (p *ServerProtocol) SendTo(ctx context.Context, id string, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+	defer func() { _ = m.Finish(err) }()
+
+	p.mu.Lock()
+	c, ok := p.conns[id]
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrServerClosed
+	}
+	if !ok {
+		return ErrConnectionNotFound
+	}
+
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	b, err := c.format.Marshal(e)
+	if err != nil {
+		return err
+	}
+	select {
+	case c.outbound <- b:
+		return nil
+	case <-c.ctx.Done():
+		return ErrConnectionNotFound
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Connections returns the open connections.
+func (p *ServerProtocol) Connections() []*Connection {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conns := make([]*Connection, 0, len(p.conns))
+	for _, c := range p.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+// Close implements protocol.Closer, closing all the connections. The status code and the reason of the
+// close frames can be set with WithCloseReason, and default to StatusGoingAway.
+func (p *ServerProtocol) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	conns := p.conns
+	p.conns = make(map[string]*Connection)
+	p.mu.Unlock()
+
+	statusCode := websocket.StatusGoingAway
+	if val := ctx.Value(codeKey{}); val != nil {
+		statusCode = val.(websocket.StatusCode)
+	}
+	reason := ""
+	if val := ctx.Value(reasonKey{}); val != nil {
+		reason = val.(string)
+	}
+	// Each close waits for the close frame of the peer
+	var wg sync.WaitGroup
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *Connection) {
+			defer wg.Done()
+			c.close(statusCode, reason)
+		}(c)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (p *ServerProtocol) register(c *Connection) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	p.conns[c.ID] = c
+	return true
+}
+
+func (p *ServerProtocol) unregister(c *Connection) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns[c.ID] == c {
+		delete(p.conns, c.ID)
+	}
+}
+
+// ServeHTTP implements http.Handler, accepting a WebSocket connection and serving it until it's closed.
+func (p *ServerProtocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	opts := p.acceptOptions
+	conn, err := websocket.Accept(rw, req, &opts)
+	if err != nil {
+		// Accept already replied to the request
+		cecontext.LoggerFrom(req.Context()).Debugw("cannot accept the ws connection", "error", err)
+		return
+	}
+	if p.readLimit > 0 {
+		conn.SetReadLimit(p.readLimit)
+	}
+	f, messageType, err := resolveFormat(conn.Subprotocol())
+	if err != nil {
+		_ = conn.Close(websocket.StatusPolicyViolation, err.Error())
+		return
+	}
+
+	c := &Connection{
+		ID:          uuid.New().String(),
+		Request:     req,
+		Subprotocol: conn.Subprotocol(),
+		ConnectedAt: time.Now(),
+		conn:        conn,
+		format:      f,
+		messageType: messageType,
+		inbound:     make(chan []byte, p.bufferSize),
+		outbound:    make(chan []byte, p.bufferSize),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.WithValue(req.Context(), connectionKey{}, c))
+	if !p.register(c) {
+		_ = conn.Close(websocket.StatusGoingAway, ErrServerClosed.Error())
+		c.cancel()
+		return
+	}
+	defer p.unregister(c)
+
+	go p.deliver(c)
+	go c.write()
+	if p.pingInterval > 0 {
+		go c.ping(p.pingInterval)
+	}
+	c.read()
+}
+
+// read reads the messages of c into its inbound queue until c is closed
+func (c *Connection) read() {
+	defer close(c.inbound)
+	for {
+		messageType, b, err := c.conn.Read(c.ctx)
+		if err != nil {
+			if c.ctx.Err() != nil {
+				// The connection is being closed, or the request was canceled
+				c.close(websocket.StatusGoingAway, "")
+				return
+			}
+			if websocket.CloseStatus(err) == -1 {
+				cecontext.LoggerFrom(c.ctx).Debugw("cannot read the ws connection", "id", c.ID, "error", err)
+			}
+			c.close(websocket.StatusInternalError, "")
+			return
+		}
+		if messageType != c.messageType {
+			cecontext.LoggerFrom(c.ctx).Debugw("dropping a ws message of the wrong type", "id", c.ID, "type", messageType)
+			continue
+		}
+		select {
+		case c.inbound <- b:
+			continue
+		default:
+		}
+		// Pause reading until the receiver catches up
+		atomic.StoreInt32(&c.blocked, 1)
+		select {
+		case c.inbound <- b:
+			atomic.StoreInt32(&c.blocked, 0)
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver passes the messages of the inbound queue of c to Receive
+func (p *ServerProtocol) deliver(c *Connection) {
+	for b := range c.inbound {
+		m := &serverMessage{Message: utils.NewStructuredMessage(c.format, bytes.NewReader(b)), ctx: detachedContext{c.ctx}}
+		select {
+		case p.incoming <- m:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// write writes the messages of the outbound queue of c until c is closed
+func (c *Connection) write() {
+	for {
+		select {
+		case b := <-c.outbound:
+			if err := c.conn.Write(c.ctx, c.messageType, b); err != nil {
+				cecontext.LoggerFrom(c.ctx).Debugw("cannot write to the ws connection", "id", c.ID, "error", err)
+				c.close(websocket.StatusInternalError, "")
+				return
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// ping closes c when it doesn't answer a ping within interval. The pongs are read by read, so no ping
+// is sent while reading is paused.
+func (c *Connection) ping(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if atomic.LoadInt32(&c.blocked) == 1 {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(c.ctx, interval)
+			err := c.conn.Ping(ctx)
+			cancel()
+			if err != nil && c.ctx.Err() == nil {
+				cecontext.LoggerFrom(c.ctx).Debugw("ws connection didn't answer the ping", "id", c.ID, "error", err)
+				c.close(websocket.StatusPolicyViolation, "ping timeout")
+				return
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+var _ protocol.Receiver = (*ServerProtocol)(nil)
+var _ protocol.Sender = (*ServerProtocol)(nil)
+var _ protocol.Closer = (*ServerProtocol)(nil)
+var _ http.Handler = (*ServerProtocol)(nil)
@@ -0,0 +1,182 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package v2
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func newTestServerProtocol(t *testing.T, opts ...ServerOption) (*ServerProtocol, *httptest.Server) {
+	p, err := NewServerProtocol(opts...)
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	t.Cleanup(func() {
+		_ = p.Close(context.Background())
+		server.Close()
+	})
+	return p, server
+}
+
+// dialTestServer connects to the server, returning once the connection is registered
+func dialTestServer(t *testing.T, p *ServerProtocol, server *httptest.Server) *ClientProtocol {
+	conns := len(p.Connections())
+	c, err := Dial(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close(context.Background()) })
+	require.Eventually(t, func() bool { return len(p.Connections()) > conns }, time.Second, time.Millisecond)
+	return c
+}
+
+func receiveTestEvent(t *testing.T, r interface {
+	Receive(context.Context) (binding.Message, error)
+}) (*cloudevents.Event, binding.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m, err := r.Receive(ctx)
+	require.NoError(t, err)
+	e, err := binding.ToEvent(ctx, m)
+	require.NoError(t, err)
+	require.NoError(t, m.Finish(nil))
+	return e, m
+}
+
+func TestServerProtocol(t *testing.T) {
+	p, server := newTestServerProtocol(t)
+	client1 := dialTestServer(t, p, server)
+	client2 := dialTestServer(t, p, server)
+
+	// Receive
+	ping := pingEvent()
+	require.NoError(t, client1.Send(context.Background(), binding.ToMessage(&ping)))
+	e, m := receiveTestEvent(t, p)
+	AssertEventEquals(t, ping, *e)
+	conn := ConnectionFrom(m.(binding.MessageContext).Context())
+	require.NotNil(t, conn)
+	require.Equal(t, JsonSubprotocol, conn.Subprotocol)
+	require.NotEmpty(t, conn.ID)
+	require.NotNil(t, conn.Request)
+
+	// Reply to the connection
+	pong := ping.Clone()
+	pong.SetID("2")
+	pong.SetType("pong")
+	require.NoError(t, p.SendTo(context.Background(), conn.ID, binding.ToMessage(&pong)))
+	e, _ = receiveTestEvent(t, client1)
+	AssertEventEquals(t, pong, *e)
+	require.Equal(t, ErrConnectionNotFound, p.SendTo(context.Background(), "unknown", binding.ToMessage(&pong)))
+
+	// Broadcast
+	broadcast := ping.Clone()
+	broadcast.SetID("3")
+	require.NoError(t, p.Send(context.Background(), binding.ToMessage(&broadcast)))
+	e, _ = receiveTestEvent(t, client1)
+	AssertEventEquals(t, broadcast, *e)
+	e, _ = receiveTestEvent(t, client2)
+	AssertEventEquals(t, broadcast, *e)
+}
+
+func TestServerProtocol_receiveAfterClose(t *testing.T) {
+	p, server := newTestServerProtocol(t)
+	client := dialTestServer(t, p, server)
+
+	ping := pingEvent()
+	require.NoError(t, client.Send(context.Background(), binding.ToMessage(&ping)))
+	require.NoError(t, client.Close(context.Background()))
+	require.Eventually(t, func() bool { return len(p.Connections()) == 0 }, 5*time.Second, time.Millisecond)
+
+	// The event is delivered after the connection is closed, with the values of its context
+	e, m := receiveTestEvent(t, p)
+	AssertEventEquals(t, ping, *e)
+	ctx := m.(binding.MessageContext).Context()
+	require.NoError(t, ctx.Err())
+	require.NotNil(t, ConnectionFrom(ctx))
+}
+
+func TestServerProtocol_unsupportedSubprotocol(t *testing.T) {
+	_, server := newTestServerProtocol(t)
+	c, _, err := websocket.Dial(context.Background(), server.URL, &websocket.DialOptions{Subprotocols: []string{"other"}})
+	require.NoError(t, err)
+	_, _, err = c.Read(context.Background())
+	require.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err))
+}
+
+func TestServerProtocol_slowConsumer(t *testing.T) {
+	p, server := newTestServerProtocol(t, WithConnectionBufferSize(1))
+	// A client not reading its events
+	dialTestServer(t, p, server)
+
+	// Random data, which isn't compressed, fills the buffers of the connection quickly
+	data := make([]byte, 256*1024)
+	_, _ = rand.Read(data)
+	e := pingEvent()
+	_ = e.SetData("application/octet-stream", data)
+	require.Eventually(t, func() bool {
+		require.NoError(t, p.Send(context.Background(), binding.ToMessage(&e)))
+		return len(p.Connections()) == 0
+	}, 10*time.Second, time.Millisecond)
+}
+
+func TestServerProtocol_ping(t *testing.T) {
+	p, server := newTestServerProtocol(t, WithPingInterval(50*time.Millisecond))
+
+	// A client reading its connection answers the pings
+	client := dialTestServer(t, p, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_, _ = client.Receive(ctx)
+	}()
+
+	// A client not reading its connection is closed
+	c, _, err := websocket.Dial(context.Background(), server.URL, &websocket.DialOptions{Subprotocols: SupportedSubprotocols})
+	require.NoError(t, err)
+	defer c.Close(websocket.StatusNormalClosure, "")
+	require.Eventually(t, func() bool { return len(p.Connections()) == 2 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return len(p.Connections()) == 1 }, 5*time.Second, time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	require.Len(t, p.Connections(), 1)
+}
+
+func TestServerProtocol_close(t *testing.T) {
+	p, server := newTestServerProtocol(t)
+	client := dialTestServer(t, p, server)
+
+	closed := make(chan error, 1)
+	go func() {
+		_, err := client.Receive(context.Background())
+		closed <- err
+	}()
+	require.NoError(t, p.Close(WithCloseReason(context.Background(), websocket.StatusNormalClosure, "bye")))
+	err := <-closed
+	require.Equal(t, websocket.StatusNormalClosure, websocket.CloseStatus(err))
+	require.Empty(t, p.Connections())
+	_, err = p.Receive(context.Background())
+	require.Equal(t, io.EOF, err)
+
+	e := pingEvent()
+	require.Equal(t, ErrServerClosed, p.Send(context.Background(), binding.ToMessage(&e)))
+}
+
+func TestServerOptions_invalid(t *testing.T) {
+	_, err := NewServerProtocol(WithPingInterval(-time.Second))
+	require.EqualError(t, err, "ws ping interval option was given a negative interval: -1s")
+	_, err = NewServerProtocol(WithConnectionBufferSize(0))
+	require.EqualError(t, err, "ws connection buffer size option was given an invalid size: 0")
+	_, err = NewServerProtocol(WithReadLimit(0))
+	require.EqualError(t, err, "ws read limit option was given an invalid limit: 0")
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
+	"github.com/cloudevents/sdk-go/sql/v2/utils"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type condition struct {
+	expression cesql.Expression
+	// identifiers are the words of the expression which might be extension names
+	identifiers []string
+}
+
+func newCondition(input string) (c *condition, err error) {
+	// The parser might panic on some malformed expressions
+	defer func() {
+		if r := recover(); r != nil {
+			c, err = nil, fmt.Errorf("invalid expression %q: %v", input, r)
+		}
+	}()
+	expression, err := cesqlparser.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return &condition{expression: expression, identifiers: expressionIdentifiers(input)}, nil
+}
+
+// guard returns a transformer applying t only if the condition matches
+func (c *condition) guard(t binding.Transformer) binding.Transformer {
+	return binding.TransformerFunc(func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		if !c.matches(reader) {
+			return nil
+		}
+		return t.Transform(reader, writer)
+	})
+}
+
+func (c *condition) matches(reader binding.MessageMetadataReader) bool {
+	var e event.Event
+	if em, ok := reader.(*binding.EventMessage); ok {
+		e = *(*event.Event)(em)
+	} else {
+		e = c.toEvent(reader)
+	}
+	res, err := c.expression.Evaluate(e)
+	if err != nil {
+		return false
+	}
+	res, err = utils.Cast(res, cesql.BooleanType)
+	if err != nil {
+		return false
+	}
+	return res.(bool)
+}
+
+// toEvent builds an event with the metadata of reader required to evaluate the expression.
+// MessageMetadataReader can't list the extensions, so only the ones named in the expression are copied.
+func (c *condition) toEvent(reader binding.MessageMetadataReader) event.Event {
+	version := spec.V1
+	if _, sv := reader.GetAttribute(spec.SpecVersion); sv != nil {
+		if v := spec.VS.Version(sv.(string)); v != nil {
+			version = v
+		}
+	}
+
+	e := event.New()
+	e.Context = version.NewContext()
+	for _, attr := range version.Attributes() {
+		if _, val := reader.GetAttribute(attr.Kind()); val != nil {
+			_ = attr.Set(e.Context, val)
+		}
+	}
+	for _, name := range c.identifiers {
+		if val := getExtension(reader, name); val != nil {
+			_ = e.Context.SetExtension(name, val)
+		}
+	}
+	return e
+}
+
+// expressionIdentifiers returns the lower cased words of a CESQL expression, skipping the string literals.
+// Keywords and function names are included too, looking them up as extensions is harmless.
+func expressionIdentifiers(input string) []string {
+	var identifiers []string
+	var quote rune
+	escaped := false
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			identifiers = append(identifiers, strings.ToLower(word.String()))
+			word.Reset()
+		}
+	}
+	for _, r := range input {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			flush()
+			quote = r
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return identifiers
+}
@@ -0,0 +1,319 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Config is the declarative configuration of a transformer pipeline.
+type Config struct {
+	// Transformers are the steps of the pipeline, applied in order.
+	Transformers []Step `json:"transformers"`
+}
+
+// Step is an entry of the pipeline. Exactly one operation must be set.
+type Step struct {
+	// If is an optional CESQL expression: when set, the operation is applied only
+	// if the expression evaluates to true. Evaluation errors, like a missing attribute, count as false.
+	If string `json:"if,omitempty"`
+
+	Rename  *Rename `json:"rename,omitempty"`
+	Copy    *Copy   `json:"copy,omitempty"`
+	Set     *Set    `json:"set,omitempty"`
+	Default *Set    `json:"default,omitempty"`
+	Delete  *Target `json:"delete,omitempty"`
+	// Version converts the event to the spec version, e.g. "1.0" or "0.3".
+	Version string `json:"version,omitempty"`
+}
+
+// Target identifies either an attribute or an extension.
+type Target struct {
+	Attribute string `json:"attribute,omitempty"`
+	Extension string `json:"extension,omitempty"`
+}
+
+// Rename renames the extension From to To.
+type Rename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Copy copies the value of the attribute From to the extension To.
+type Copy struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Set sets the target attribute or extension to Value.
+// When used as Default, the target is set only if missing.
+// Value is a text/template: the attributes and the extensions of the event are available as
+// fields of the template data, e.g. "com.example.{{ .type }}". Missing fields render as empty strings.
+type Set struct {
+	Target
+	Value string `json:"value"`
+}
+
+// ConfigError is returned when an entry of the configuration is not valid.
+type ConfigError struct {
+	// Path of the failing entry, e.g. "transformers[2].rename.to"
+	Path string
+	Err  error
+}
+
+// Error implements error.Error
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+func invalid(path string, format string, args ...interface{}) error {
+	return &ConfigError{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+// Load parses a pipeline configuration, in YAML or JSON, and builds its transformers.
+// Unknown fields are rejected.
+func Load(data []byte) (binding.Transformers, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+	return config.Build()
+}
+
+// Build validates the configuration and returns the corresponding transformers.
+// Validation errors are *ConfigError pointing to the failing entry.
+func (c Config) Build() (binding.Transformers, error) {
+	transformers := make(binding.Transformers, 0, len(c.Transformers))
+	for i, step := range c.Transformers {
+		t, err := step.build(fmt.Sprintf("transformers[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		transformers = append(transformers, t)
+	}
+	return transformers, nil
+}
+
+func (s Step) build(path string) (binding.Transformer, error) {
+	var ops []string
+	if s.Rename != nil {
+		ops = append(ops, "rename")
+	}
+	if s.Copy != nil {
+		ops = append(ops, "copy")
+	}
+	if s.Set != nil {
+		ops = append(ops, "set")
+	}
+	if s.Default != nil {
+		ops = append(ops, "default")
+	}
+	if s.Delete != nil {
+		ops = append(ops, "delete")
+	}
+	if s.Version != "" {
+		ops = append(ops, "version")
+	}
+	if len(ops) != 1 {
+		return nil, invalid(path, "exactly one of rename, copy, set, default, delete or version must be set, found [%s]", strings.Join(ops, ", "))
+	}
+
+	var t binding.Transformer
+	var err error
+	switch {
+	case s.Rename != nil:
+		t, err = s.Rename.build(path + ".rename")
+	case s.Copy != nil:
+		t, err = s.Copy.build(path + ".copy")
+	case s.Set != nil:
+		t, err = s.Set.build(path+".set", false)
+	case s.Default != nil:
+		t, err = s.Default.build(path+".default", true)
+	case s.Delete != nil:
+		t, err = s.Delete.buildDelete(path + ".delete")
+	default:
+		t, err = buildVersion(path+".version", s.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.If != "" {
+		c, err := newCondition(s.If)
+		if err != nil {
+			return nil, &ConfigError{Path: path + ".if", Err: err}
+		}
+		t = c.guard(t)
+	}
+	return t, nil
+}
+
+func (r *Rename) build(path string) (binding.Transformer, error) {
+	if err := validateExtension(path+".from", r.From); err != nil {
+		return nil, err
+	}
+	if err := validateExtension(path+".to", r.To); err != nil {
+		return nil, err
+	}
+	if r.From == r.To {
+		return nil, invalid(path, "from and to must be different")
+	}
+	return binding.TransformerFunc(func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		val := getExtension(reader, r.From)
+		if val == nil {
+			return nil
+		}
+		if err := writer.SetExtension(r.To, val); err != nil {
+			return err
+		}
+		return writer.SetExtension(r.From, nil)
+	}), nil
+}
+
+func (c *Copy) build(path string) (binding.Transformer, error) {
+	kind, err := attributeKind(path+".from", c.From)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExtension(path+".to", c.To); err != nil {
+		return nil, err
+	}
+	return binding.TransformerFunc(func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		_, val := reader.GetAttribute(kind)
+		if val == nil {
+			return nil
+		}
+		return writer.SetExtension(c.To, val)
+	}), nil
+}
+
+func (s *Set) build(path string, onlyIfMissing bool) (binding.Transformer, error) {
+	if err := s.Target.validate(path); err != nil {
+		return nil, err
+	}
+	tmpl, err := newValueTemplate(path, s.Value)
+	if err != nil {
+		return nil, &ConfigError{Path: path + ".value", Err: err}
+	}
+
+	if s.Attribute != "" {
+		kind, err := attributeKind(path+".attribute", s.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		if kind == spec.SpecVersion {
+			return nil, invalid(path+".attribute", "specversion can be changed only using version")
+		}
+		return binding.TransformerFunc(func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+			attr, old := reader.GetAttribute(kind)
+			if attr == nil {
+				// The spec version of this message doesn't support this attribute, skip this
+				return nil
+			}
+			if onlyIfMissing && old != nil {
+				return nil
+			}
+			val, err := tmpl.render(reader)
+			if err != nil {
+				return err
+			}
+			return writer.SetAttribute(attr, val)
+		}), nil
+	}
+
+	return binding.TransformerFunc(func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		if onlyIfMissing && getExtension(reader, s.Extension) != nil {
+			return nil
+		}
+		val, err := tmpl.render(reader)
+		if err != nil {
+			return err
+		}
+		return writer.SetExtension(s.Extension, val)
+	}), nil
+}
+
+func (t *Target) buildDelete(path string) (binding.Transformer, error) {
+	if err := t.validate(path); err != nil {
+		return nil, err
+	}
+	if t.Extension != "" {
+		return transformer.DeleteExtension(t.Extension), nil
+	}
+	kind, err := attributeKind(path+".attribute", t.Attribute)
+	if err != nil {
+		return nil, err
+	}
+	if kind.IsRequired() {
+		return nil, invalid(path+".attribute", "required attribute %s cannot be deleted", t.Attribute)
+	}
+	return transformer.DeleteAttribute(kind), nil
+}
+
+func buildVersion(path string, version string) (binding.Transformer, error) {
+	v := spec.VS.Version(version)
+	if v == nil {
+		return nil, invalid(path, "unknown spec version %s", version)
+	}
+	return transformer.Version(v), nil
+}
+
+func (t Target) validate(path string) error {
+	switch {
+	case t.Attribute != "" && t.Extension != "":
+		return invalid(path, "only one of attribute or extension must be set")
+	case t.Attribute != "":
+		_, err := attributeKind(path+".attribute", t.Attribute)
+		return err
+	case t.Extension != "":
+		return validateExtension(path+".extension", t.Extension)
+	default:
+		return invalid(path, "one of attribute or extension must be set")
+	}
+}
+
+// attributeKind returns the kind of the attribute with the provided name, in any of the supported spec versions
+func attributeKind(path string, name string) (spec.Kind, error) {
+	for _, v := range spec.VS.Versions() {
+		if attr := v.Attribute(name); attr != nil {
+			return attr.Kind(), nil
+		}
+	}
+	return 0, invalid(path, "unknown attribute %q", name)
+}
+
+func validateExtension(path string, name string) error {
+	if !event.IsExtensionNameValid(name) {
+		return invalid(path, "invalid extension name %q", name)
+	}
+	if _, err := attributeKind(path, name); err == nil {
+		return invalid(path, "%s is an attribute, not an extension", name)
+	}
+	return nil
+}
+
+// getExtension returns the value of the extension name, or nil if it's missing.
+// Some readers return a zero value instead of nil for a missing extension, use types.IsZero() to test it.
+func getExtension(reader binding.MessageMetadataReader, name string) interface{} {
+	if val := reader.GetExtension(name); !types.IsZero(val) {
+		return val
+	}
+	return nil
+}
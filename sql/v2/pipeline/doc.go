@@ -0,0 +1,22 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package pipeline builds binding.Transformers from a declarative configuration in YAML or JSON,
+so attribute rewrites can be configured without writing Go code:
+
+	transformers:
+	- rename: {from: traceid, to: correlationid}
+	- copy: {from: source, to: origin}
+	- set: {attribute: type, value: "com.example.{{ .type }}"}
+	  if: "source LIKE '/legacy/%'"
+	- default: {extension: tenant, value: "default"}
+	- delete: {extension: internal}
+	- version: "1.0"
+
+Each step supports an optional CESQL condition in if.
+Configuration errors are reported as *ConfigError, pointing to the failing entry.
+*/
+package pipeline
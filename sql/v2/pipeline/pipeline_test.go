@@ -0,0 +1,136 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/sql/v2/pipeline"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func testEvent() event.Event {
+	e := event.New()
+	e.SetID("1")
+	e.SetType("created")
+	e.SetSource("/legacy/orders")
+	e.SetSubject("order")
+	e.SetExtension("traceid", "abc")
+	e.SetExtension("internal", "secret")
+	return e
+}
+
+func TestLoad(t *testing.T) {
+	config := `
+transformers:
+- rename: {from: traceid, to: correlationid}
+- copy: {from: source, to: origin}
+- set: {attribute: type, value: "com.example.{{ .type }}.{{ .correlationid }}"}
+  if: "source LIKE '/legacy/%' AND EXISTS correlationid"
+- set: {attribute: subject, value: "skipped"}
+  if: "source = '/other'"
+- default: {extension: tenant, value: "default"}
+- default: {attribute: subject, value: "not applied"}
+- delete: {extension: internal}
+- delete: {attribute: subject}
+`
+	transformers, err := pipeline.Load([]byte(config))
+	require.NoError(t, err)
+
+	want := event.New()
+	want.SetID("1")
+	want.SetType("com.example.created.abc")
+	want.SetSource("/legacy/orders")
+	want.SetExtension("correlationid", "abc")
+	want.SetExtension("origin", "/legacy/orders")
+	want.SetExtension("tenant", "default")
+
+	input := testEvent()
+	for _, m := range []binding.Message{(*binding.EventMessage)(&input), bindingtest.MustCreateMockBinaryMessage(input)} {
+		t.Run(m.ReadEncoding().String(), func(t *testing.T) {
+			got, err := binding.ToEvent(context.TODO(), m, transformers)
+			require.NoError(t, err)
+			require.Equal(t, want.Context.AsV1(), got.Context.AsV1())
+		})
+	}
+}
+
+func TestLoad_Version(t *testing.T) {
+	transformers, err := pipeline.Load([]byte(`{"transformers": [{"version": "0.3"}]}`))
+	require.NoError(t, err)
+
+	e := testEvent()
+	got, err := binding.ToEvent(context.TODO(), (*binding.EventMessage)(&e), transformers)
+	require.NoError(t, err)
+	require.Equal(t, event.CloudEventsVersionV03, got.SpecVersion())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		config   string
+		wantPath string
+	}{
+		"no operation": {
+			config:   `transformers: [{if: "true"}]`,
+			wantPath: "transformers[0]",
+		},
+		"two operations": {
+			config:   `transformers: [{version: "1.0", delete: {extension: a}}]`,
+			wantPath: "transformers[0]",
+		},
+		"rename to attribute": {
+			config:   `transformers: [{version: "1.0"}, {rename: {from: a, to: type}}]`,
+			wantPath: "transformers[1].rename.to",
+		},
+		"copy unknown attribute": {
+			config:   `transformers: [{copy: {from: unknown, to: a}}]`,
+			wantPath: "transformers[0].copy.from",
+		},
+		"set both targets": {
+			config:   `transformers: [{set: {attribute: type, extension: a, value: b}}]`,
+			wantPath: "transformers[0].set",
+		},
+		"set specversion": {
+			config:   `transformers: [{set: {attribute: specversion, value: "0.3"}}]`,
+			wantPath: "transformers[0].set.attribute",
+		},
+		"bad template": {
+			config:   `transformers: [{default: {extension: a, value: "{{ .type"}}]`,
+			wantPath: "transformers[0].default.value",
+		},
+		"delete required attribute": {
+			config:   `transformers: [{delete: {attribute: id}}]`,
+			wantPath: "transformers[0].delete.attribute",
+		},
+		"unknown version": {
+			config:   `transformers: [{version: "2.0"}]`,
+			wantPath: "transformers[0].version",
+		},
+		"bad condition": {
+			config:   `transformers: [{version: "1.0", if: "type = 'a')"}]`,
+			wantPath: "transformers[0].if",
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := pipeline.Load([]byte(tc.config))
+			var configErr *pipeline.ConfigError
+			require.True(t, errors.As(err, &configErr), "unexpected error %v", err)
+			require.Equal(t, tc.wantPath, configErr.Path)
+		})
+	}
+}
+
+func TestLoad_UnknownField(t *testing.T) {
+	_, err := pipeline.Load([]byte(`transformers: [{renam: {from: a, to: b}}]`))
+	require.Error(t, err)
+}
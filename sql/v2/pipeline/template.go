@@ -0,0 +1,69 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+var templateFieldRegexp = regexp.MustCompile(`\.([a-zA-Z0-9]+)`)
+
+type valueTemplate struct {
+	template *template.Template
+	// fields are the names referenced by the template which might be extension names
+	fields []string
+}
+
+func newValueTemplate(name string, value string) (*valueTemplate, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, match := range templateFieldRegexp.FindAllStringSubmatch(value, -1) {
+		fields = append(fields, strings.ToLower(match[1]))
+	}
+	return &valueTemplate{template: t, fields: fields}, nil
+}
+
+// render executes the template with the attributes of reader, named as in spec 1.0,
+// and the extensions referenced by the template
+func (t *valueTemplate) render(reader binding.MessageMetadataReader) (string, error) {
+	data := make(map[string]string)
+	for _, attr := range spec.V1.Attributes() {
+		if _, val := reader.GetAttribute(attr.Kind()); val != nil {
+			s, err := types.Format(val)
+			if err != nil {
+				return "", err
+			}
+			data[attr.Name()] = s
+		}
+	}
+	for _, name := range t.fields {
+		if _, ok := data[name]; ok {
+			continue
+		}
+		if val := getExtension(reader, name); val != nil {
+			s, err := types.Format(val)
+			if err != nil {
+				return "", err
+			}
+			data[name] = s
+		}
+	}
+
+	var sb strings.Builder
+	if err := t.template.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -19,8 +19,10 @@ import (
 
 	otelObs "github.com/cloudevents/sdk-go/observability/opentelemetry/v2/client"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/client"
 	event "github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/observability"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
 )
@@ -413,6 +415,59 @@ func TestRecordReceivedMalformedEvent(t *testing.T) {
 	}
 }
 
+func TestRecordRejectedRequest(t *testing.T) {
+	sr, _ := configureOtelTestSdk()
+	ctx := context.Background()
+
+	var os client.RejectionRecorder = otelObs.NewOTelObservabilityService()
+
+	// act
+	os.RecordRejectedRequest(ctx, http.ErrRateLimited)
+
+	spans := sr.Ended()
+	assert.Equal(t, 1, len(spans))
+
+	span := spans[0]
+	assert.Equal(t, "cloudevents.client.rejected receive", span.Name())
+	assert.Equal(t, trace.SpanKindConsumer, span.SpanKind())
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(string(semconv.CodeFunctionKey), "RecordRejectedRequest"),
+	}, span.Attributes())
+
+	assert.Equal(t, 1, len(span.Events()))
+	assert.Equal(t, semconv.ExceptionEventName, span.Events()[0].Name)
+	attrsMap := getSpanEventMap(span.Events()[0].Attributes)
+	assert.Equal(t, http.ErrRateLimited.Error(), attrsMap[string(semconv.ExceptionMessageKey)])
+}
+
+func TestRecordSendingEvent_lineage(t *testing.T) {
+	sr, _ := configureOtelTestSdk()
+	e := event.New()
+	e.SetID("child")
+	e.SetType("type")
+	e.SetSource("/source")
+	extensions.LineageExtension{CausationID: "parent", CorrelationID: "root"}.AddLineageAttributes(&e)
+
+	os := otelObs.NewOTelObservabilityService()
+	_, cb := os.RecordSendingEvent(context.Background(), e)
+	cb(nil)
+
+	spans := sr.Ended()
+	assert.Equal(t, 1, len(spans))
+	want := []attribute.KeyValue{
+		attribute.String(string(semconv.CodeFunctionKey), "RecordSendingEvent"),
+		attribute.String(observability.SpecversionAttr, "1.0"),
+		attribute.String(observability.IdAttr, "child"),
+		attribute.String(observability.TypeAttr, "type"),
+		attribute.String(observability.SourceAttr, "/source"),
+		attribute.String(observability.CausationidAttr, "parent"),
+		attribute.String(observability.CorrelationidAttr, "root"),
+	}
+	if !reflect.DeepEqual(spans[0].Attributes(), want) {
+		t.Errorf("p = %v, want %v", spans[0].Attributes(), want)
+	}
+}
+
 func getSpanEventMap(evtAttrs []attribute.KeyValue) map[string]string {
 	attr := map[string]string{}
 	for _, v := range evtAttrs {
@@ -101,6 +101,7 @@ var (
 	WithEventDefaulter = client.WithEventDefaulter
 	WithUUIDs          = client.WithUUIDs
 	WithTimeNow        = client.WithTimeNow
+	WithIDGenerator    = client.WithIDGenerator
 	// Deprecated: this is now noop and will be removed in future releases.
 	WithTracePropagation = client.WithTracePropagation()
 
@@ -0,0 +1,165 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package buffering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// ForkPolicy defines how the outcomes of the children of a forked message
+// are combined to finish the original message.
+type ForkPolicy int
+
+const (
+	// FailIfAnyFails finishes the original message with an error if at least one child fails.
+	FailIfAnyFails ForkPolicy = iota
+	// FailIfAllFail finishes the original message with an error only if every child fails.
+	FailIfAllFail
+)
+
+// ForkError is the error used to finish the original message of a fork.
+// Errors contains the outcome of each child, in the same order of the children returned by ForkMessage:
+// the entry of a child that succeeded is nil.
+type ForkError struct {
+	Errors []error
+}
+
+// Error implements error.Error
+func (e *ForkError) Error() string {
+	var failed []string
+	for i, err := range e.Errors {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("[%d] %s", i, err.Error()))
+		}
+	}
+	return fmt.Sprintf("%d of %d forks failed: %s", len(failed), len(e.Errors), strings.Join(failed, "; "))
+}
+
+// Is reports whether any of the children errors matches target.
+func (e *ForkError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if err != nil && errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the children errors that matches target.
+func (e *ForkError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if err != nil && errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForkMessage reads m once into a buffer and returns n child messages sharing it,
+// so the same message can be written to n destinations.
+// Every child must be finished: when the last child is finished, the buffer is released and m is finished
+// with nil or with a *ForkError, depending on policy. A child outcome is a failure when its Finish error
+// is not an ACK, as defined by protocol.IsACK.
+// transformers can be nil and this function guarantees that they are invoked only once during the encoding process.
+func ForkMessage(ctx context.Context, m binding.Message, n int, policy ForkPolicy, transformers ...binding.Transformer) ([]binding.Message, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of forks: %d", n)
+	}
+	buffered, err := CopyMessage(ctx, m, transformers...)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &fork{
+		original:  m,
+		buffered:  buffered,
+		policy:    policy,
+		errs:      make([]error, n),
+		remaining: n,
+	}
+	children := make([]binding.Message, n)
+	for i := range children {
+		children[i] = &forkedMessage{Message: buffered, fork: f, index: i}
+	}
+	return children, nil
+}
+
+type fork struct {
+	original binding.Message
+	buffered binding.Message
+	policy   ForkPolicy
+
+	mutex     sync.Mutex
+	errs      []error
+	remaining int
+}
+
+func (f *fork) finish(index int, err error) error {
+	f.mutex.Lock()
+	if !protocol.IsACK(err) {
+		f.errs[index] = err
+	}
+	f.remaining--
+	last := f.remaining == 0
+	f.mutex.Unlock()
+
+	if !last {
+		return nil
+	}
+	_ = f.buffered.Finish(nil)
+	return f.original.Finish(f.result())
+}
+
+func (f *fork) result() error {
+	failed := 0
+	for _, err := range f.errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == 0 || (f.policy == FailIfAllFail && failed < len(f.errs)) {
+		return nil
+	}
+	return &ForkError{Errors: f.errs}
+}
+
+type forkedMessage struct {
+	binding.Message
+	fork  *fork
+	index int
+	once  sync.Once
+}
+
+func (m *forkedMessage) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	return m.Message.(binding.MessageMetadataReader).GetAttribute(k)
+}
+
+func (m *forkedMessage) GetExtension(s string) interface{} {
+	return m.Message.(binding.MessageMetadataReader).GetExtension(s)
+}
+
+func (m *forkedMessage) GetWrappedMessage() binding.Message {
+	return m.Message
+}
+
+// Finish records the outcome of this child. Only the first call is taken into account.
+func (m *forkedMessage) Finish(err error) error {
+	var result error
+	m.once.Do(func() {
+		result = m.fork.finish(m.index, err)
+	})
+	return result
+}
+
+var _ binding.MessageWrapper = (*forkedMessage)(nil)
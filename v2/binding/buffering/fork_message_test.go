@@ -0,0 +1,109 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package buffering
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestForkMessage(t *testing.T) {
+	e := FullEvent()
+
+	for _, m := range []binding.Message{MustCreateMockBinaryMessage(e), MustCreateMockStructuredMessage(t, e)} {
+		t.Run(m.ReadEncoding().String(), func(t *testing.T) {
+			finishCalled := 0
+			original := binding.WithFinish(m, func(err error) {
+				finishCalled++
+				require.NoError(t, err)
+			})
+
+			children, err := ForkMessage(context.Background(), original, 10, FailIfAnyFails)
+			require.NoError(t, err)
+			require.Len(t, children, 10)
+
+			wg := sync.WaitGroup{}
+			for _, child := range children {
+				wg.Add(1)
+				go func(child binding.Message) {
+					defer wg.Done()
+					got, err := binding.ToEvent(context.Background(), child)
+					require.NoError(t, err)
+					AssertEventEquals(t, ConvertEventExtensionsToString(t, e), ConvertEventExtensionsToString(t, *got))
+					require.NoError(t, child.Finish(protocol.ResultACK))
+				}(child)
+			}
+			wg.Wait()
+			require.Equal(t, 1, finishCalled)
+		})
+	}
+}
+
+func TestForkMessage_Policy(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name    string
+		policy  ForkPolicy
+		results []error
+		wantErr bool
+	}{
+		{name: "any, all succeed", policy: FailIfAnyFails, results: []error{nil, protocol.ResultACK, nil}},
+		{name: "any, one fails", policy: FailIfAnyFails, results: []error{nil, failure, nil}, wantErr: true},
+		{name: "all, one fails", policy: FailIfAllFail, results: []error{nil, failure, protocol.ResultNACK}},
+		{name: "all, all fail", policy: FailIfAllFail, results: []error{failure, failure, protocol.ResultNACK}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var finishErr error
+			finishCalled := false
+			original := binding.WithFinish(MustCreateMockBinaryMessage(FullEvent()), func(err error) {
+				finishCalled = true
+				finishErr = err
+			})
+
+			children, err := ForkMessage(context.Background(), original, len(tt.results), tt.policy)
+			require.NoError(t, err)
+			for i, child := range children {
+				require.False(t, finishCalled)
+				require.NoError(t, child.Finish(tt.results[i]))
+				// Finishing a child twice is a no-op
+				require.NoError(t, child.Finish(failure))
+			}
+			require.True(t, finishCalled)
+
+			if !tt.wantErr {
+				require.NoError(t, finishErr)
+				return
+			}
+			var forkErr *ForkError
+			require.True(t, errors.As(finishErr, &forkErr))
+			require.Len(t, forkErr.Errors, len(tt.results))
+			require.True(t, errors.Is(finishErr, failure))
+			for i, res := range tt.results {
+				if protocol.IsACK(res) {
+					require.NoError(t, forkErr.Errors[i])
+				} else {
+					require.Equal(t, res, forkErr.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestForkMessage_InvalidCount(t *testing.T) {
+	_, err := ForkMessage(context.Background(), MustCreateMockBinaryMessage(FullEvent()), 0, FailIfAnyFails)
+	require.Error(t, err)
+}
@@ -0,0 +1,224 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// ErrLimitExceeded is returned, wrapped, when a message exceeds the configured Limits
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the resources used to read an inbound message, protecting the receiver
+// from oversized or malicious events. A zero value field means no limit.
+type Limits struct {
+	// MaxEventSize is the maximum size in bytes of an event: the whole event in structured mode,
+	// the attributes, extensions and data in binary mode.
+	MaxEventSize int64
+	// MaxExtensions is the maximum number of extensions of an event.
+	MaxExtensions int
+	// MaxExtensionValueLength is the maximum length of the string representation of an extension value.
+	MaxExtensionValueLength int
+}
+
+// CheckSize returns an error wrapping ErrLimitExceeded if size exceeds MaxEventSize.
+func (l Limits) CheckSize(size int64) error {
+	if l.MaxEventSize > 0 && size > l.MaxEventSize {
+		return fmt.Errorf("%w: event size exceeds %d bytes", ErrLimitExceeded, l.MaxEventSize)
+	}
+	return nil
+}
+
+// CheckExtensions returns an error wrapping ErrLimitExceeded if count exceeds MaxExtensions.
+func (l Limits) CheckExtensions(count int) error {
+	if l.MaxExtensions > 0 && count > l.MaxExtensions {
+		return fmt.Errorf("%w: event has more than %d extensions", ErrLimitExceeded, l.MaxExtensions)
+	}
+	return nil
+}
+
+// CheckExtensionValue returns an error wrapping ErrLimitExceeded if the string representation
+// of the value of the extension name exceeds MaxExtensionValueLength.
+func (l Limits) CheckExtensionValue(name string, value interface{}) error {
+	if l.MaxExtensionValueLength <= 0 || value == nil {
+		return nil
+	}
+	s, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	if len(s) > l.MaxExtensionValueLength {
+		return fmt.Errorf("%w: extension %s is longer than %d", ErrLimitExceeded, name, l.MaxExtensionValueLength)
+	}
+	return nil
+}
+
+// CheckEvent returns an error wrapping ErrLimitExceeded if the extensions or the data of e exceed the limits.
+func (l Limits) CheckEvent(e event.Event) error {
+	if err := l.CheckExtensions(len(e.Extensions())); err != nil {
+		return err
+	}
+	for name, value := range e.Extensions() {
+		if err := l.CheckExtensionValue(name, value); err != nil {
+			return err
+		}
+	}
+	return l.CheckSize(int64(len(e.Data())))
+}
+
+// LimitReader returns a reader of r failing with an error wrapping ErrLimitExceeded
+// as soon as more than MaxEventSize bytes are read.
+func (l Limits) LimitReader(r io.Reader) io.Reader {
+	if l.MaxEventSize <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, limits: l, remaining: l.MaxEventSize}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	limits    Limits
+	remaining int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.remaining < 0 {
+		return 0, lr.limits.CheckSize(lr.limits.MaxEventSize + 1)
+	}
+	// Read one byte more than the remaining ones, to detect the overflow
+	if int64(len(p)) > lr.remaining+1 {
+		p = p[:lr.remaining+1]
+	}
+	n, err := lr.r.Read(p)
+	lr.remaining -= int64(n)
+	if lr.remaining < 0 {
+		return n + int(lr.remaining), lr.limits.CheckSize(lr.limits.MaxEventSize + 1)
+	}
+	return n, err
+}
+
+type limitedMessage struct {
+	Message
+	limits Limits
+}
+
+// WithLimits returns a wrapper for m enforcing limits while m is read.
+// Reading the wrapped message fails with an error wrapping ErrLimitExceeded when a limit is exceeded.
+// In structured mode, the extensions are checked when the message is converted to an event using ToEvent.
+// Messages with EncodingEvent are already in memory and are not checked.
+func WithLimits(m Message, limits Limits) Message {
+	return &limitedMessage{Message: m, limits: limits}
+}
+
+func (m *limitedMessage) ReadStructured(ctx context.Context, writer StructuredWriter) error {
+	if err := m.Message.ReadStructured(ctx, &limitedStructuredWriter{StructuredWriter: writer, limits: m.limits}); err != nil {
+		return err
+	}
+	if b, ok := writer.(*messageToEventBuilder); ok {
+		return m.limits.CheckEvent(*(*event.Event)(b))
+	}
+	return nil
+}
+
+func (m *limitedMessage) ReadBinary(ctx context.Context, writer BinaryWriter) error {
+	return m.Message.ReadBinary(ctx, &limitedBinaryWriter{BinaryWriter: writer, limits: m.limits})
+}
+
+func (m *limitedMessage) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	return m.Message.(MessageMetadataReader).GetAttribute(k)
+}
+
+func (m *limitedMessage) GetExtension(s string) interface{} {
+	return m.Message.(MessageMetadataReader).GetExtension(s)
+}
+
+func (m *limitedMessage) GetWrappedMessage() Message {
+	return m.Message
+}
+
+// Context returns the context of the first wrapped message implementing MessageContext, if any.
+func (m *limitedMessage) Context() context.Context {
+	for msg := m.Message; msg != nil; {
+		if mctx, ok := msg.(MessageContext); ok {
+			return mctx.Context()
+		}
+		mw, ok := msg.(MessageWrapper)
+		if !ok {
+			break
+		}
+		msg = mw.GetWrappedMessage()
+	}
+	return nil
+}
+
+var _ MessageWrapper = (*limitedMessage)(nil)
+var _ MessageContext = (*limitedMessage)(nil)
+
+type limitedStructuredWriter struct {
+	StructuredWriter
+	limits Limits
+}
+
+func (w *limitedStructuredWriter) SetStructuredEvent(ctx context.Context, format format.Format, event io.Reader) error {
+	return w.StructuredWriter.SetStructuredEvent(ctx, format, w.limits.LimitReader(event))
+}
+
+type limitedBinaryWriter struct {
+	BinaryWriter
+	limits     Limits
+	size       int64
+	extensions int
+}
+
+func (w *limitedBinaryWriter) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	if err := w.addSize(value); err != nil {
+		return err
+	}
+	return w.BinaryWriter.SetAttribute(attribute, value)
+}
+
+func (w *limitedBinaryWriter) SetExtension(name string, value interface{}) error {
+	if value != nil {
+		w.extensions++
+		if err := w.limits.CheckExtensions(w.extensions); err != nil {
+			return err
+		}
+		if err := w.limits.CheckExtensionValue(name, value); err != nil {
+			return err
+		}
+		if err := w.addSize(value); err != nil {
+			return err
+		}
+	}
+	return w.BinaryWriter.SetExtension(name, value)
+}
+
+func (w *limitedBinaryWriter) SetData(data io.Reader) error {
+	if w.limits.MaxEventSize > 0 {
+		data = &limitedReader{r: data, limits: w.limits, remaining: w.limits.MaxEventSize - w.size}
+	}
+	return w.BinaryWriter.SetData(data)
+}
+
+func (w *limitedBinaryWriter) addSize(value interface{}) error {
+	if w.limits.MaxEventSize <= 0 || value == nil {
+		return nil
+	}
+	s, err := types.Format(value)
+	if err != nil {
+		return err
+	}
+	w.size += int64(len(s))
+	return w.limits.CheckSize(w.size)
+}
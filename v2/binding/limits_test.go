@@ -0,0 +1,73 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestWithLimits(t *testing.T) {
+	e := test.MinEvent()
+	e.SetExtension("exta", "short")
+	e.SetExtension("extb", strings.Repeat("a", 100))
+	require.NoError(t, e.SetData(event.TextPlain, strings.Repeat("d", 1000)))
+
+	tests := []struct {
+		name    string
+		limits  binding.Limits
+		wantErr bool
+	}{
+		{name: "no limits", limits: binding.Limits{}},
+		{name: "within limits", limits: binding.Limits{MaxEventSize: 2000, MaxExtensions: 2, MaxExtensionValueLength: 100}},
+		{name: "event too large", limits: binding.Limits{MaxEventSize: 1000}, wantErr: true},
+		{name: "too many extensions", limits: binding.Limits{MaxExtensions: 1}, wantErr: true},
+		{name: "extension value too long", limits: binding.Limits{MaxExtensionValueLength: 99}, wantErr: true},
+	}
+	for _, tt := range tests {
+		for _, m := range []binding.Message{bindingtest.MustCreateMockBinaryMessage(e), bindingtest.MustCreateMockStructuredMessage(t, e)} {
+			t.Run(tt.name+"/"+m.ReadEncoding().String(), func(t *testing.T) {
+				got, err := binding.ToEvent(context.TODO(), binding.WithLimits(m, tt.limits))
+				if tt.wantErr {
+					require.True(t, errors.Is(err, binding.ErrLimitExceeded), "unexpected error %v", err)
+					return
+				}
+				require.NoError(t, err)
+				test.AssertEventEquals(t, e, *got)
+			})
+		}
+	}
+}
+
+func TestLimits_LimitReader(t *testing.T) {
+	limits := binding.Limits{MaxEventSize: 10}
+
+	b, err := ioutil.ReadAll(limits.LimitReader(bytes.NewReader(make([]byte, 10))))
+	require.NoError(t, err)
+	require.Len(t, b, 10)
+
+	_, err = ioutil.ReadAll(limits.LimitReader(bytes.NewReader(make([]byte, 11))))
+	require.True(t, errors.Is(err, binding.ErrLimitExceeded))
+}
+
+func TestLimits_CheckEvent(t *testing.T) {
+	e := test.FullEvent()
+	require.NoError(t, binding.Limits{}.CheckEvent(e))
+	require.NoError(t, binding.Limits{MaxExtensions: len(e.Extensions())}.CheckEvent(e))
+	require.Error(t, binding.Limits{MaxExtensions: len(e.Extensions()) - 1}.CheckEvent(e))
+	require.Error(t, binding.Limits{MaxEventSize: int64(len(e.Data()) - 1)}.CheckEvent(e))
+}
@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// ToEventStream translates a Message to an Event like ToEvent, but returns the data as an io.Reader
+// instead of reading it in memory when the message is in binary encoding, so very large payloads can be
+// streamed from the transport. The returned Event has no data, and the returned io.Reader is valid only
+// until the message is finished. Structured messages and Event messages are read in memory.
+// transformers can be nil and this function guarantees that they are invoked only once during the encoding process.
+func ToEventStream(ctx context.Context, message MessageReader, transformers ...Transformer) (*event.Event, io.Reader, error) {
+	if message == nil {
+		return nil, nil, nil
+	}
+
+	if message.ReadEncoding() != EncodingBinary {
+		e, err := ToEvent(ctx, message, transformers...)
+		if err != nil {
+			return nil, nil, err
+		}
+		stripped := e.Clone()
+		stripped.DataEncoded = nil
+		stripped.DataBase64 = false
+		return &stripped, bytes.NewReader(e.Data()), nil
+	}
+
+	e := event.New()
+	encoder := &eventStreamBuilder{messageToEventBuilder: (*messageToEventBuilder)(&e)}
+	if err := writeBinary(ctx, message, encoder); err != nil {
+		return nil, nil, err
+	}
+	if err := Transformers(transformers).Transform((*EventMessage)(&e), encoder); err != nil {
+		return nil, nil, err
+	}
+	if encoder.data == nil {
+		return &e, bytes.NewReader(nil), nil
+	}
+	return &e, encoder.data, nil
+}
+
+// eventStreamBuilder builds an Event without data, keeping the data reader
+type eventStreamBuilder struct {
+	*messageToEventBuilder
+	data io.Reader
+}
+
+func (b *eventStreamBuilder) SetData(data io.Reader) error {
+	b.data = data
+	return nil
+}
+
+// StreamMessage is a binary Message made of the attributes of an Event and of a data reader.
+// Writing a StreamMessage to a binary writer streams the data without buffering it in memory.
+// A StreamMessage can be read only once.
+type StreamMessage struct {
+	Event *event.Event
+	Data  io.Reader
+}
+
+// NewStreamMessage returns a StreamMessage with the attributes of e and the data read from data.
+// The data of e, if any, is ignored. If data is an io.Closer, it's closed when the message is finished.
+func NewStreamMessage(e *event.Event, data io.Reader) *StreamMessage {
+	return &StreamMessage{Event: e, Data: data}
+}
+
+var _ Message = (*StreamMessage)(nil)
+var _ MessageMetadataReader = (*StreamMessage)(nil)
+
+func (m *StreamMessage) ReadEncoding() Encoding {
+	return EncodingBinary
+}
+
+func (m *StreamMessage) ReadStructured(context.Context, StructuredWriter) error {
+	return ErrNotStructured
+}
+
+func (m *StreamMessage) ReadBinary(ctx context.Context, b BinaryWriter) error {
+	if err := eventContextToBinaryWriter(m.Event.Context, b); err != nil {
+		return err
+	}
+	if m.Data != nil {
+		return b.SetData(m.Data)
+	}
+	return nil
+}
+
+func (m *StreamMessage) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
+	return (*EventMessage)(m.Event).GetAttribute(k)
+}
+
+func (m *StreamMessage) GetExtension(name string) interface{} {
+	return (*EventMessage)(m.Event).GetExtension(name)
+}
+
+func (m *StreamMessage) Finish(error) error {
+	if c, ok := m.Data.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding_test
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestToEventStream(t *testing.T) {
+	test.EachEvent(t, test.Events(), func(t *testing.T, e event.Event) {
+		e = test.ConvertEventExtensionsToString(t, e.Clone())
+		want := e.Clone()
+		want.DataEncoded = nil
+		want.DataBase64 = false
+
+		for _, m := range []binding.Message{
+			bindingtest.MustCreateMockBinaryMessage(e),
+			bindingtest.MustCreateMockStructuredMessage(t, e),
+			binding.ToMessage(&e),
+		} {
+			got, data, err := binding.ToEventStream(context.TODO(), m)
+			require.NoError(t, err)
+			test.AssertEventContextEquals(t, want.Context, got.Context)
+			require.Nil(t, got.Data())
+
+			b, err := ioutil.ReadAll(data)
+			require.NoError(t, err)
+			require.Equal(t, string(e.Data()), string(b))
+		}
+	})
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStreamMessage(t *testing.T) {
+	e := test.FullEvent()
+	e = test.ConvertEventExtensionsToString(t, e)
+	data := &closeRecorder{Reader: strings.NewReader("streamed data")}
+
+	m := binding.NewStreamMessage(&e, data)
+	require.Equal(t, binding.EncodingBinary, m.ReadEncoding())
+	require.Equal(t, binding.ErrNotStructured, m.ReadStructured(context.TODO(), nil))
+
+	got, err := binding.ToEvent(context.TODO(), m)
+	require.NoError(t, err)
+	test.AssertEventContextEquals(t, e.Context, got.Context)
+	require.Equal(t, "streamed data", string(got.Data()))
+
+	require.NoError(t, m.Finish(nil))
+	require.True(t, data.closed)
+}
@@ -0,0 +1,54 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+// Avoid DCE
+var Event *event.Event
+var Err error
+var ID, Type string
+
+func BenchmarkToEvent(b *testing.B) {
+	m := MustCreateMockBinaryMessage(FullEvent())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Event, Err = binding.ToEvent(context.TODO(), m)
+	}
+}
+
+// BenchmarkToPooledEvent validates the full event, whose data schema is checked by parsing all the attributes
+// like ToEvent does
+func BenchmarkToPooledEvent(b *testing.B) {
+	m := MustCreateMockBinaryMessage(FullEvent())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var release func()
+		Event, release, Err = binding.ToPooledEvent(context.TODO(), m)
+		Err = Event.Validate()
+		release()
+	}
+}
+
+// BenchmarkToPooledEvent_lazy reads only the id and the type, which are not parsed
+func BenchmarkToPooledEvent_lazy(b *testing.B) {
+	m := MustCreateMockBinaryMessage(FullEvent())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var release func()
+		Event, release, Err = binding.ToPooledEvent(context.TODO(), m)
+		ID, Type = Event.ID(), Event.Type()
+		release()
+	}
+}
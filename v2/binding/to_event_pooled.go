@@ -0,0 +1,488 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// pooledEvent holds an Event and the storage reused to build it.
+// The attributes read from the message are kept as they are, and parsed into context only when needed,
+// see lazyEventContext.
+type pooledEvent struct {
+	event   event.Event
+	context event.EventContextV1
+	data    bytes.Buffer
+
+	// The attributes and the extensions read from the message
+	attributes [spec.Time + 1]spec.Attribute
+	values     [spec.Time + 1]interface{}
+	extensions []extensionValue
+
+	// parsed is true once the attributes and the extensions are parsed into context,
+	// errs holds the parsing errors by attribute name and err the first one
+	parsed bool
+	errs   event.ValidationError
+	err    error
+
+	// Storage of the optional attributes of context, to avoid allocating them
+	dataContentType string
+	subject         string
+	time            types.Timestamp
+}
+
+type extensionValue struct {
+	name  string
+	value interface{}
+}
+
+// maxPooledDataSize is the capacity above which the data buffer isn't kept in the pool, so that a single
+// large event doesn't pin its buffer for all the events reusing it
+const maxPooledDataSize = 64 * 1024
+
+var pooledEvents = sync.Pool{
+	New: func() interface{} {
+		p := &pooledEvent{}
+		p.reset()
+		return p
+	},
+}
+
+// reset clears the event, keeping the extensions map, the extensions slice and the data buffer to reuse them
+func (p *pooledEvent) reset() {
+	extensions := p.context.Extensions
+	for k := range extensions {
+		delete(extensions, k)
+	}
+	p.context = event.EventContextV1{Extensions: extensions}
+	p.event = event.Event{Context: (*lazyEventContext)(p)}
+	p.data.Reset()
+
+	p.attributes = [spec.Time + 1]spec.Attribute{}
+	p.values = [spec.Time + 1]interface{}{}
+	for i := range p.extensions {
+		p.extensions[i] = extensionValue{}
+	}
+	p.extensions = p.extensions[:0]
+	p.parsed = false
+	p.errs = nil
+	p.err = nil
+}
+
+func (p *pooledEvent) release() {
+	p.reset()
+	if p.data.Cap() > maxPooledDataSize {
+		p.data = bytes.Buffer{}
+	}
+	pooledEvents.Put(p)
+}
+
+// parse parses the attributes and the extensions read from the message into context, once.
+// It returns the first parsing error, if any.
+func (p *pooledEvent) parse() error {
+	if p.parsed {
+		return p.err
+	}
+	p.parsed = true
+	for kind, value := range p.values {
+		if value != nil {
+			p.addError(spec.Kind(kind).String(), p.setAttribute(p.attributes[kind], value))
+		}
+	}
+	for _, ext := range p.extensions {
+		value, err := ext.value, error(nil)
+		if value != nil {
+			value, err = types.Validate(value)
+		}
+		if err == nil {
+			err = p.context.SetExtension(ext.name, value)
+		}
+		p.addError(ext.name, err)
+	}
+	return p.err
+}
+
+func (p *pooledEvent) addError(name string, err error) {
+	if err == nil {
+		return
+	}
+	if p.errs == nil {
+		p.errs = event.ValidationError{}
+		p.err = err
+	}
+	p.errs[name] = err
+}
+
+// setAttribute sets an attribute of context using its storage when possible
+func (p *pooledEvent) setAttribute(attribute spec.Attribute, value interface{}) error {
+	switch attribute.Kind() {
+	case spec.DataContentType:
+		if str, err := types.ToString(value); err == nil && strings.TrimSpace(str) != "" {
+			p.dataContentType = strings.TrimSpace(str)
+			p.context.DataContentType = &p.dataContentType
+			return nil
+		}
+	case spec.Subject:
+		if str, err := types.ToString(value); err == nil && strings.TrimSpace(str) != "" {
+			p.subject = strings.TrimSpace(str)
+			p.context.Subject = &p.subject
+			return nil
+		}
+	case spec.Time:
+		if t, err := types.ToTime(value); err == nil && !t.IsZero() {
+			p.time = types.Timestamp{Time: t}
+			p.context.Time = &p.time
+			return nil
+		}
+	}
+	return attribute.Set(&p.context, value)
+}
+
+// ToPooledEvent works like ToEvent, but reuses the Event instances and their buffers across calls
+// to reduce the allocations on high throughput receive paths.
+//
+// release must be called exactly once when the event is no longer used: after that, the event, its context
+// and its data must not be accessed anymore, so they must not be retained nor copied shallowly.
+// Clone the event with event.Clone() to keep it.
+//
+// The attributes are parsed lazily: the id, the type, the subject, the data content type and the extensions
+// are read as they were received, while the first access to the other attributes or a modification of the event
+// parse all of them. The validation checks the values received without parsing them, unless they're invalid or
+// of an unexpected type. Hence, the errors of the invalid attribute values are returned by event.Validate()
+// rather than by this function.
+//
+// When the message provides its data as a *bytes.Buffer, like the transports owning the received buffer, the data
+// of the event references it without copying it: the event must be released before the message is finished.
+// The other data are copied into a pooled buffer.
+//
+// Only binary messages are pooled, the other messages are converted using ToEvent and release is a no-op.
+// Binary messages with another spec version than 1.0 are parsed eagerly.
+// transformers can be nil and this function guarantees that they are invoked only once during the encoding process.
+func ToPooledEvent(ctx context.Context, message MessageReader, transformers ...Transformer) (e *event.Event, release func(), err error) {
+	if message == nil || message.ReadEncoding() != EncodingBinary {
+		e, err = ToEvent(ctx, message, transformers...)
+		return e, func() {}, err
+	}
+
+	p := pooledEvents.Get().(*pooledEvent)
+	encoder := (*pooledEventBuilder)(p)
+	if err := writeBinary(ctx, message, encoder); err != nil {
+		p.release()
+		return nil, nil, err
+	}
+	if err := Transformers(transformers).Transform((*EventMessage)(&p.event), (*messageToEventBuilder)(&p.event)); err != nil {
+		p.release()
+		return nil, nil, err
+	}
+	return &p.event, p.release, nil
+}
+
+// pooledEventBuilder is a BinaryWriter building the event of a pooledEvent
+type pooledEventBuilder pooledEvent
+
+var _ BinaryWriter = (*pooledEventBuilder)(nil)
+
+func (b *pooledEventBuilder) Start(ctx context.Context) error {
+	return nil
+}
+
+func (b *pooledEventBuilder) End(ctx context.Context) error {
+	return nil
+}
+
+func (b *pooledEventBuilder) SetAttribute(attribute spec.Attribute, value interface{}) error {
+	if b.event.Context != (*lazyEventContext)(b) {
+		return (*messageToEventBuilder)(&b.event).SetAttribute(attribute, value)
+	}
+
+	if attribute.Kind() == spec.SpecVersion {
+		if str, err := types.ToString(value); err == nil && str == event.CloudEventsVersionV1 {
+			return nil
+		}
+		// Not a 1.0 event: parse the attributes read so far and convert the context like ToEvent
+		if err := (*pooledEvent)(b).parse(); err != nil {
+			return err
+		}
+		b.event.Context = &b.context
+		return (*messageToEventBuilder)(&b.event).SetAttribute(attribute, value)
+	}
+
+	b.attributes[attribute.Kind()] = attribute
+	b.values[attribute.Kind()] = value
+	return nil
+}
+
+func (b *pooledEventBuilder) SetExtension(name string, value interface{}) error {
+	if b.event.Context != (*lazyEventContext)(b) {
+		return (*messageToEventBuilder)(&b.event).SetExtension(name, value)
+	}
+	b.extensions = append(b.extensions, extensionValue{name: name, value: value})
+	return nil
+}
+
+func (b *pooledEventBuilder) SetData(data io.Reader) error {
+	buf, ok := data.(*bytes.Buffer)
+	if !ok {
+		buf = &b.data
+		if _, err := io.Copy(buf, data); err != nil {
+			return err
+		}
+	}
+	if buf.Len() > 0 {
+		b.event.DataEncoded = buf.Bytes()
+	}
+	return nil
+}
+
+// lazyEventContext is the event.EventContext of a pooled event. The id, the type, the subject, the data content type
+// and the extensions are read from the values received, as long as they are strings. The other methods parse
+// the attributes into the pooled event.EventContextV1 and delegate to it.
+type lazyEventContext pooledEvent
+
+var _ event.EventContext = (*lazyEventContext)(nil)
+
+// parsedContext returns the parsed context
+func (c *lazyEventContext) parsedContext() *event.EventContextV1 {
+	_ = (*pooledEvent)(c).parse()
+	return &c.context
+}
+
+// stringAttribute returns the value of a string attribute without parsing the context, when possible
+func (c *lazyEventContext) stringAttribute(kind spec.Kind) (string, bool) {
+	if c.parsed {
+		return "", false
+	}
+	switch v := c.values[kind].(type) {
+	case nil:
+		return "", true
+	case string:
+		return strings.TrimSpace(v), true
+	}
+	return "", false
+}
+
+func (c *lazyEventContext) AsV03() *event.EventContextV03 {
+	return c.parsedContext().AsV03()
+}
+
+func (c *lazyEventContext) AsV1() *event.EventContextV1 {
+	return c.parsedContext().AsV1()
+}
+
+func (c *lazyEventContext) GetSpecVersion() string {
+	return event.CloudEventsVersionV1
+}
+
+func (c *lazyEventContext) GetType() string {
+	if s, ok := c.stringAttribute(spec.Type); ok {
+		return s
+	}
+	return c.parsedContext().GetType()
+}
+
+func (c *lazyEventContext) GetSource() string {
+	return c.parsedContext().GetSource()
+}
+
+func (c *lazyEventContext) GetSubject() string {
+	if s, ok := c.stringAttribute(spec.Subject); ok {
+		return s
+	}
+	return c.parsedContext().GetSubject()
+}
+
+func (c *lazyEventContext) GetID() string {
+	if s, ok := c.stringAttribute(spec.ID); ok {
+		return s
+	}
+	return c.parsedContext().GetID()
+}
+
+func (c *lazyEventContext) GetTime() time.Time {
+	return c.parsedContext().GetTime()
+}
+
+func (c *lazyEventContext) GetDataSchema() string {
+	return c.parsedContext().GetDataSchema()
+}
+
+func (c *lazyEventContext) GetDataContentType() string {
+	if s, ok := c.stringAttribute(spec.DataContentType); ok {
+		return s
+	}
+	return c.parsedContext().GetDataContentType()
+}
+
+func (c *lazyEventContext) DeprecatedGetDataContentEncoding() string {
+	return ""
+}
+
+func (c *lazyEventContext) GetDataMediaType() (string, error) {
+	if s, ok := c.stringAttribute(spec.DataContentType); ok {
+		if i := strings.IndexRune(s, ';'); i != -1 {
+			return strings.TrimSpace(s[0:i]), nil
+		}
+		return s, nil
+	}
+	return c.parsedContext().GetDataMediaType()
+}
+
+func (c *lazyEventContext) ExtensionAs(name string, obj interface{}) error {
+	return c.parsedContext().ExtensionAs(name, obj)
+}
+
+func (c *lazyEventContext) GetExtensions() map[string]interface{} {
+	return c.parsedContext().GetExtensions()
+}
+
+func (c *lazyEventContext) GetExtension(name string) (interface{}, error) {
+	if !c.parsed {
+		// The last value of the extension wins, like when it's parsed
+		for i := len(c.extensions) - 1; i >= 0; i-- {
+			if !strings.EqualFold(c.extensions[i].name, name) {
+				continue
+			}
+			if s, ok := c.extensions[i].value.(string); ok {
+				return s, nil
+			}
+			break
+		}
+	}
+	return c.parsedContext().GetExtension(name)
+}
+
+func (c *lazyEventContext) SetType(t string) error {
+	return c.parsedContext().SetType(t)
+}
+
+func (c *lazyEventContext) SetSource(s string) error {
+	return c.parsedContext().SetSource(s)
+}
+
+func (c *lazyEventContext) SetSubject(s string) error {
+	return c.parsedContext().SetSubject(s)
+}
+
+func (c *lazyEventContext) SetID(id string) error {
+	return c.parsedContext().SetID(id)
+}
+
+func (c *lazyEventContext) SetTime(t time.Time) error {
+	return c.parsedContext().SetTime(t)
+}
+
+func (c *lazyEventContext) SetDataSchema(s string) error {
+	return c.parsedContext().SetDataSchema(s)
+}
+
+func (c *lazyEventContext) SetDataContentType(ct string) error {
+	return c.parsedContext().SetDataContentType(ct)
+}
+
+func (c *lazyEventContext) DeprecatedSetDataContentEncoding(e string) error {
+	return c.parsedContext().DeprecatedSetDataContentEncoding(e)
+}
+
+func (c *lazyEventContext) SetExtension(name string, value interface{}) error {
+	return c.parsedContext().SetExtension(name, value)
+}
+
+// Validate returns the errors of the parsing of the attributes, along with the errors of the parsed context.
+// The attributes aren't parsed when the values received are valid strings.
+func (c *lazyEventContext) Validate() event.ValidationError {
+	if !c.parsed && c.validValues() {
+		return nil
+	}
+	errs := c.parsedContext().Validate()
+	if len(c.errs) == 0 {
+		return errs
+	}
+	merged := event.ValidationError{}
+	for k, err := range errs {
+		merged[k] = err
+	}
+	for k, err := range c.errs {
+		merged[k] = err
+	}
+	return merged
+}
+
+// validValues returns true if the values received are strings making a valid context. It returns false when
+// they're not, or can't be checked without parsing them, so that Validate reports the errors of the parsed context.
+func (c *lazyEventContext) validValues() bool {
+	for _, kind := range []spec.Kind{spec.ID, spec.Type} {
+		if s, ok := c.values[kind].(string); !ok || strings.TrimSpace(s) == "" {
+			return false
+		}
+	}
+	switch v := c.values[spec.Source].(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return false
+		}
+		if _, err := url.Parse(v); err != nil {
+			return false
+		}
+	case types.URIRef:
+		if strings.TrimSpace(v.String()) == "" {
+			return false
+		}
+	default:
+		return false
+	}
+	if v := c.values[spec.DataContentType]; v != nil {
+		s, ok := v.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return false
+		}
+		if _, _, err := mime.ParseMediaType(strings.TrimSpace(s)); err != nil {
+			return false
+		}
+	}
+	if v := c.values[spec.Subject]; v != nil {
+		if s, ok := v.(string); !ok || strings.TrimSpace(s) == "" {
+			return false
+		}
+	}
+	switch v := c.values[spec.Time].(type) {
+	case nil, types.Timestamp, time.Time:
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	if c.values[spec.DataSchema] != nil {
+		return false
+	}
+	for _, ext := range c.extensions {
+		if _, ok := ext.value.(string); !ok || !event.IsExtensionNameValid(ext.name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the parsed context, which can be retained after the event is released
+func (c *lazyEventContext) Clone() event.EventContext {
+	return c.parsedContext().Clone()
+}
+
+func (c *lazyEventContext) String() string {
+	return c.parsedContext().String()
+}
@@ -0,0 +1,188 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	. "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	. "github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestToPooledEvent(t *testing.T) {
+	EachEvent(t, Events(), func(t *testing.T, v event.Event) {
+		for _, m := range []binding.Message{MustCreateMockStructuredMessage(t, v), MustCreateMockBinaryMessage(v)} {
+			t.Run(m.ReadEncoding().String(), func(t *testing.T) {
+				// Convert twice, to reuse the pooled event
+				for i := 0; i < 2; i++ {
+					got, release, err := binding.ToPooledEvent(context.Background(), m)
+					require.NoError(t, err)
+					AssertEventEquals(t, ConvertEventExtensionsToString(t, v), ConvertEventExtensionsToString(t, *got))
+					release()
+				}
+			})
+		}
+	})
+}
+
+func TestToPooledEvent_reset(t *testing.T) {
+	withExtensions := FullEvent()
+	withExtensions.SetExtension("exta", "a")
+	withoutExtensions := MinEvent()
+
+	got, release, err := binding.ToPooledEvent(context.Background(), MustCreateMockBinaryMessage(withExtensions))
+	require.NoError(t, err)
+	require.NotEmpty(t, got.Extensions())
+	release()
+
+	got, release, err = binding.ToPooledEvent(context.Background(), MustCreateMockBinaryMessage(withoutExtensions))
+	require.NoError(t, err)
+	defer release()
+	require.Empty(t, got.Extensions())
+	require.Nil(t, got.Data())
+	AssertEventEquals(t, withoutExtensions, *got)
+}
+
+// bufferBinaryMessage provides its data as a *bytes.Buffer, like the transports owning the buffer
+type bufferBinaryMessage struct {
+	*MockBinaryMessage
+}
+
+func (m bufferBinaryMessage) ReadBinary(ctx context.Context, b binding.BinaryWriter) error {
+	for k, v := range m.Metadata {
+		if err := b.SetAttribute(k, v); err != nil {
+			return err
+		}
+	}
+	return b.SetData(bytes.NewBuffer(m.Body))
+}
+
+func TestToPooledEvent_dataNotCopied(t *testing.T) {
+	e := MinEvent()
+	require.NoError(t, e.SetData(event.TextPlain, "hello"))
+	m := bufferBinaryMessage{MustCreateMockBinaryMessage(e).(*MockBinaryMessage)}
+
+	got, release, err := binding.ToPooledEvent(context.Background(), m)
+	require.NoError(t, err)
+	defer release()
+	require.Equal(t, []byte("hello"), got.Data())
+	require.Equal(t, &m.Body[0], &got.Data()[0])
+}
+
+func TestToPooledEvent_bad_spec_version(t *testing.T) {
+	inputEvent := FullEvent()
+
+	inputMessage := MustCreateMockBinaryMessage(inputEvent)
+	inputMessage.(*MockBinaryMessage).Metadata[spec.VS.Version(inputEvent.SpecVersion()).AttributeFromKind(spec.SpecVersion)] = "0.1.1"
+
+	got, _, err := binding.ToPooledEvent(context.Background(), inputMessage)
+	require.Nil(t, got)
+	require.EqualError(t, err, "unrecognized event version 0.1.1")
+}
+
+func TestToPooledEvent_lazy(t *testing.T) {
+	want := FullEvent()
+	m := MustCreateMockBinaryMessage(want).(*MockBinaryMessage)
+	m.Metadata[spec.VS.Version(want.SpecVersion()).AttributeFromKind(spec.Source)] = "%zz"
+
+	// The invalid source is reported by the validation
+	got, release, err := binding.ToPooledEvent(context.Background(), m)
+	require.NoError(t, err)
+	require.Equal(t, want.ID(), got.ID())
+	require.Equal(t, want.Type(), got.Type())
+	require.Equal(t, want.Subject(), got.Subject())
+	require.Equal(t, want.DataContentType(), got.DataContentType())
+	require.Equal(t, "exstring", mustGetExtension(t, got, "exstring"))
+	err = got.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "source")
+	release()
+
+	// The parsed event is marshaled like the event
+	got, release, err = binding.ToPooledEvent(context.Background(), MustCreateMockBinaryMessage(want))
+	require.NoError(t, err)
+	defer release()
+	require.NoError(t, got.Validate())
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	require.NoError(t, err)
+	require.JSONEq(t, string(wantJSON), string(gotJSON))
+
+	// The clone outlives the pooled event
+	clone := got.Clone()
+	require.IsType(t, &event.EventContextV1{}, clone.Context)
+	AssertEventEquals(t, ConvertEventExtensionsToString(t, want), ConvertEventExtensionsToString(t, clone))
+}
+
+func TestToPooledEvent_validate(t *testing.T) {
+	valid := MinEvent()
+	valid.SetSubject("subject")
+	valid.SetDataContentType(event.ApplicationJSON)
+	valid.SetExtension("exstring", "value")
+	version := spec.VS.Version(valid.SpecVersion())
+
+	tests := map[string]func(m *MockBinaryMessage){
+		"valid":                   func(m *MockBinaryMessage) {},
+		"string source":           func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.Source)] = "/source" },
+		"invalid source":          func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.Source)] = "%zz" },
+		"empty id":                func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.ID)] = " " },
+		"empty subject":           func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.Subject)] = "" },
+		"invalid datacontenttype": func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.DataContentType)] = "a/b;" },
+		"invalid time":            func(m *MockBinaryMessage) { m.Metadata[version.AttributeFromKind(spec.Time)] = "yesterday" },
+		"invalid extension name":  func(m *MockBinaryMessage) { m.Extensions["ex-string"] = "value" },
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := MustCreateMockBinaryMessage(valid).(*MockBinaryMessage)
+			tt(m)
+			// The errors of the values received are reported by ToEvent or by the validation
+			want, wantErr := binding.ToEvent(context.Background(), m)
+			if wantErr == nil {
+				wantErr = want.Validate()
+			}
+
+			got, release, err := binding.ToPooledEvent(context.Background(), m)
+			require.NoError(t, err)
+			defer release()
+			gotErr := got.Validate()
+			if wantErr == nil {
+				require.NoError(t, gotErr)
+			} else {
+				require.Error(t, gotErr)
+			}
+		})
+	}
+
+	// The valid values aren't parsed by the validation
+	m := MustCreateMockBinaryMessage(valid)
+	validate := func(parse bool) float64 {
+		return testing.AllocsPerRun(10, func() {
+			got, release, err := binding.ToPooledEvent(context.Background(), m)
+			require.NoError(t, err)
+			if parse {
+				_ = got.Source()
+			}
+			require.NoError(t, got.Validate())
+			release()
+		})
+	}
+	require.Less(t, validate(false), validate(true))
+}
+
+func mustGetExtension(t *testing.T, e *event.Event, name string) interface{} {
+	v, err := e.Context.GetExtension(name)
+	require.NoError(t, err)
+	return v
+}
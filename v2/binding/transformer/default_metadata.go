@@ -10,6 +10,7 @@ import (
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
@@ -27,3 +28,31 @@ func (a addTimeNow) Transform(reader binding.MessageMetadataReader, writer bindi
 	}
 	return nil
 }
+
+// DefaultID sets the cloudevents id attribute, if missing, to an id generated by generator.
+// When the message is converted to an event, generator gets the whole event,
+// otherwise it gets an event with the attributes of the message but without the data.
+func DefaultID(generator event.IDGenerator) binding.TransformerFunc {
+	return func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		attr, id := reader.GetAttribute(spec.ID)
+		if id != nil {
+			return nil
+		}
+		var e event.Event
+		if em, ok := reader.(*binding.EventMessage); ok {
+			e = *(*event.Event)(em)
+		} else {
+			e = event.New()
+			for _, a := range spec.V1.Attributes() {
+				if _, v := reader.GetAttribute(a.Kind()); v != nil && a.Kind() != spec.SpecVersion {
+					_ = a.Set(e.Context, v)
+				}
+			}
+		}
+		newID, err := generator.NewID(e)
+		if err != nil {
+			return err
+		}
+		return writer.SetAttribute(attr, newID)
+	}
+}
@@ -64,3 +64,46 @@ func TestAddTimeNow(t *testing.T) {
 		},
 	})
 }
+
+func TestDefaultID(t *testing.T) {
+	eventWithoutID := MinEvent()
+	eventCtx := eventWithoutID.Context.AsV1()
+	eventCtx.ID = ""
+	eventWithoutID.Context = eventCtx
+
+	eventWithID := MinEvent()
+
+	generator := event.IDGeneratorFunc(func(e event.Event) (string, error) {
+		return "generated-" + e.Type(), nil
+	})
+	assertGeneratedID := func(t *testing.T, ev event.Event) {
+		require.Equal(t, "generated-"+ev.Type(), ev.ID())
+	}
+
+	RunTransformerTests(t, context.Background(), []TransformerTestArgs{
+		{
+			Name:         "No change to id to Mock Binary message",
+			InputMessage: MustCreateMockBinaryMessage(eventWithID.Clone()),
+			WantEvent:    eventWithID.Clone(),
+			Transformers: binding.Transformers{DefaultID(generator)},
+		},
+		{
+			Name:         "No change to id to Event message",
+			InputEvent:   eventWithID,
+			WantEvent:    eventWithID,
+			Transformers: binding.Transformers{DefaultID(generator)},
+		},
+		{
+			Name:         "Add id to Mock Binary message",
+			InputMessage: MustCreateMockBinaryMessage(eventWithoutID.Clone()),
+			AssertFunc:   assertGeneratedID,
+			Transformers: binding.Transformers{DefaultID(generator)},
+		},
+		{
+			Name:         "Add id to Event message",
+			InputEvent:   eventWithoutID,
+			AssertFunc:   assertGeneratedID,
+			Transformers: binding.Transformers{DefaultID(generator)},
+		},
+	})
+}
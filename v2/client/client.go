@@ -47,9 +47,24 @@ type Client interface {
 	// * func(event.Event) (*event.Event, protocol.Result)
 	// * func(context.Context, event.Event) *event.Event
 	// * func(context.Context, event.Event) (*event.Event, protocol.Result)
+	// To stream the data of the received events, fn can take the data as an io.Reader
+	// in addition to the event without data. The reader is valid until fn returns:
+	// * func(context.Context, event.Event, io.Reader)
+	// * func(context.Context, event.Event, io.Reader) protocol.Result
+	// * func(context.Context, event.Event, io.Reader) *event.Event
+	// * func(context.Context, event.Event, io.Reader) (*event.Event, protocol.Result)
 	StartReceiver(ctx context.Context, fn interface{}) error
 }
 
+// StreamSender is implemented by the clients able to stream the data of the sent events.
+type StreamSender interface {
+	// SendStream will transmit the given event over the client's configured transport,
+	// reading its data from data instead of the event. With transports supporting the binary
+	// encoding the data is streamed without being buffered in memory.
+	// If data is an io.Closer, it's closed when the event has been sent.
+	SendStream(ctx context.Context, event event.Event, data io.Reader) protocol.Result
+}
+
 // New produces a new client with the provided transport object and applied
 // client options.
 func New(obj interface{}, opts ...Option) (Client, error) {
@@ -78,6 +93,9 @@ func New(obj interface{}, opts ...Option) (Client, error) {
 	if err := c.applyOptions(opts...); err != nil {
 		return nil, err
 	}
+	if err := recordRejections(obj, c.observabilityService); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
@@ -96,7 +114,13 @@ type ceClient struct {
 	invoker                   Invoker
 	receiverMu                sync.Mutex
 	eventDefaulterFns         []EventDefaulter
+	outboundEventInterceptors []OutboundEventInterceptor
+	inboundEventInterceptors  []InboundEventInterceptor
 	pollGoroutines            int
+	limits                    *binding.Limits
+	inboundValidation         ValidationMode
+	outboundValidation        ValidationMode
+	pooledEvents              bool
 }
 
 func (c *ceClient) applyOptions(opts ...Option) error {
@@ -124,7 +148,12 @@ func (c *ceClient) Send(ctx context.Context, e event.Event) protocol.Result {
 			e = fn(ctx, e)
 		}
 	}
-	if err = e.Validate(); err != nil {
+	for _, fn := range c.outboundEventInterceptors {
+		if err = fn(ctx, &e); err != nil {
+			return err
+		}
+	}
+	if err = validateEvent(ctx, c.outboundValidation, c.observabilityService, &e); err != nil {
 		return err
 	}
 
@@ -135,6 +164,43 @@ func (c *ceClient) Send(ctx context.Context, e event.Event) protocol.Result {
 	return err
 }
 
+// SendStream implements StreamSender.
+// Event defaulters and outbound interceptors are applied to the event without data.
+func (c *ceClient) SendStream(ctx context.Context, e event.Event, data io.Reader) protocol.Result {
+	var err error
+	if c.sender == nil {
+		err = errors.New("sender not set")
+		return err
+	}
+
+	for _, f := range c.outboundContextDecorators {
+		ctx = f(ctx)
+	}
+
+	e.DataEncoded = nil
+	e.DataBase64 = false
+	if len(c.eventDefaulterFns) > 0 {
+		for _, fn := range c.eventDefaulterFns {
+			e = fn(ctx, e)
+		}
+	}
+	for _, fn := range c.outboundEventInterceptors {
+		if err = fn(ctx, &e); err != nil {
+			return err
+		}
+	}
+	if err = validateEvent(ctx, c.outboundValidation, c.observabilityService, &e); err != nil {
+		return err
+	}
+
+	ctx, cb := c.observabilityService.RecordSendingEvent(ctx, e)
+	err = c.sender.Send(binding.WithForceBinary(ctx), binding.NewStreamMessage(&e, data))
+	defer cb(err)
+	return err
+}
+
+var _ StreamSender = (*ceClient)(nil)
+
 func (c *ceClient) Request(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
 	var resp *event.Event
 	var err error
@@ -152,8 +218,13 @@ func (c *ceClient) Request(ctx context.Context, e event.Event) (*event.Event, pr
 			e = fn(ctx, e)
 		}
 	}
+	for _, fn := range c.outboundEventInterceptors {
+		if err = fn(ctx, &e); err != nil {
+			return nil, err
+		}
+	}
 
-	if err = e.Validate(); err != nil {
+	if err = validateEvent(ctx, c.outboundValidation, c.observabilityService, &e); err != nil {
 		return nil, err
 	}
 
@@ -201,7 +272,16 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 		return fmt.Errorf("client already has a receiver")
 	}
 
-	invoker, err := newReceiveInvoker(fn, c.observabilityService, c.inboundContextDecorators, c.eventDefaulterFns...)
+	invoker, err := newReceiveInvoker(fn, receiveInvokerConfig{
+		observabilityService:     c.observabilityService,
+		eventDefaulterFns:        c.eventDefaulterFns,
+		inboundContextDecorators: c.inboundContextDecorators,
+		inboundInterceptors:      c.inboundEventInterceptors,
+		outboundInterceptors:     c.outboundEventInterceptors,
+		inboundValidation:        c.inboundValidation,
+		outboundValidation:       c.outboundValidation,
+		pooledEvents:             c.pooledEvents,
+	})
 	if err != nil {
 		return err
 	}
@@ -245,9 +325,17 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 
 				if err != nil {
 					cecontext.LoggerFrom(ctx).Warn("Error while receiving a message: ", err)
+					if msg == nil && protocol.IsNACK(err) {
+						// The protocol rejected an incoming message before passing it to the client
+						c.observabilityService.RecordReceivedMalformedEvent(ctx, err)
+					}
 					continue
 				}
 
+				if c.limits != nil {
+					msg = binding.WithLimits(msg, *c.limits)
+				}
+
 				// Do not block on the invoker.
 				wg.Add(1)
 				go func() {
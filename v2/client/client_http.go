@@ -6,6 +6,8 @@
 package client
 
 import (
+	nethttp "net/http"
+
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
 )
 
@@ -33,3 +35,19 @@ func NewHTTP(opts ...http.Option) (Client, error) {
 // the observability service passed as an option, or client.NewClientHTTP from
 // package github.com/cloudevents/sdk-go/observability/opencensus/v2/client
 var NewDefault = NewHTTP
+
+// recordRejections passes the requests rejected by an HTTP protocol to the observability service,
+// if it implements RejectionRecorder
+func recordRejections(obj interface{}, observabilityService ObservabilityService) error {
+	p, ok := obj.(*http.Protocol)
+	if !ok {
+		return nil
+	}
+	recorder, ok := observabilityService.(RejectionRecorder)
+	if !ok {
+		return nil
+	}
+	return http.WithRejectionObserver(func(req *nethttp.Request, err error) {
+		recorder.RecordRejectedRequest(req.Context(), err)
+	})(p)
+}
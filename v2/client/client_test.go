@@ -8,8 +8,10 @@ package client_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -446,3 +448,117 @@ func isImportantHeader(h string) bool {
 	}
 	return true
 }
+
+// patternReader produces size bytes without knowing its length upfront, like a stream would
+type patternReader struct {
+	size int64
+	read int64
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	if r.read >= r.size {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.size-r.read {
+		p = p[:r.size-r.read]
+	}
+	for i := range p {
+		p[i] = byte(r.read + int64(i))
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestClientStream(t *testing.T) {
+	const size = 32 << 20
+
+	p, err := cehttp.New(cehttp.WithPort(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := sha256.New()
+	_, _ = io.Copy(want, &patternReader{size: size})
+
+	type received struct {
+		event event.Event
+		sum   []byte
+		n     int64
+	}
+	receivedCh := make(chan received, 1)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go func() {
+		err := c.StartReceiver(ctx, func(ctx context.Context, e event.Event, data io.Reader) protocol.Result {
+			h := sha256.New()
+			n, err := io.Copy(h, data)
+			if err != nil {
+				return err
+			}
+			receivedCh <- received{event: e, sum: h.Sum(nil), n: n}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("failed to start receiver %s", err.Error())
+		}
+	}()
+	for p.GetListeningPort() <= 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sp, err := cehttp.New(cehttp.WithTarget(fmt.Sprintf("http://localhost:%d", p.GetListeningPort())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := client.New(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := event.New()
+	e.SetID("stream")
+	e.SetType("unit.test.client.stream")
+	e.SetSource("/unit/test/client")
+	e.SetDataContentType("application/octet-stream")
+	result := sc.(client.StreamSender).SendStream(context.TODO(), e, &patternReader{size: size})
+	if !protocol.IsACK(result) {
+		t.Fatalf("expected ACK, got %v", result)
+	}
+
+	got := <-receivedCh
+	if diff := cmp.Diff(int64(size), got.n); diff != "" {
+		t.Errorf("unexpected data length (-want, +got) = %v", diff)
+	}
+	if !bytes.Equal(want.Sum(nil), got.sum) {
+		t.Errorf("unexpected data checksum")
+	}
+	if diff := cmp.Diff("stream", got.event.ID()); diff != "" {
+		t.Errorf("unexpected event (-want, +got) = %v", diff)
+	}
+	if got.event.Data() != nil {
+		t.Errorf("unexpected event data")
+	}
+}
+
+func TestClientStream_inboundInterceptors(t *testing.T) {
+	p, err := cehttp.New(cehttp.WithPort(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New(p, client.WithInboundEventInterceptor(func(ctx context.Context, e *event.Event) (context.Context, error) {
+		return ctx, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The interceptors would see the streamed event without its data
+	err = c.StartReceiver(context.TODO(), func(ctx context.Context, e event.Event, data io.Reader) {})
+	if err == nil {
+		t.Fatal("expected an error for a streaming receiver with inbound event interceptors")
+	}
+}
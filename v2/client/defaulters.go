@@ -9,6 +9,7 @@ import (
 	"context"
 	"time"
 
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/event"
 
 	"github.com/google/uuid"
@@ -30,6 +31,25 @@ func DefaultIDToUUIDIfNotSet(ctx context.Context, event event.Event) event.Event
 	return event
 }
 
+// NewDefaultIDIfNotSet returns a defaulter that will inspect the provided event
+// and assign an id generated by generator to context.ID if it is found to be empty.
+func NewDefaultIDIfNotSet(generator event.IDGenerator) EventDefaulter {
+	return func(ctx context.Context, event event.Event) event.Event {
+		if event.Context != nil {
+			if event.ID() == "" {
+				id, err := generator.NewID(event)
+				if err != nil {
+					cecontext.LoggerFrom(ctx).Errorf("failed to generate the event id: %v", err)
+					return event
+				}
+				event.Context = event.Context.Clone()
+				event.SetID(id)
+			}
+		}
+		return event
+	}
+}
+
 // DefaultTimeToNowIfNotSet will inspect the provided event and assign a new
 // Timestamp to context.Time if it is found to be nil or zero.
 func DefaultTimeToNowIfNotSet(ctx context.Context, event event.Event) event.Event {
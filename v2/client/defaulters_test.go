@@ -7,6 +7,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -64,6 +65,44 @@ func TestDefaultIDToUUIDIfNotSetImmutable(t *testing.T) {
 	}
 }
 
+func TestNewDefaultIDIfNotSet(t *testing.T) {
+	generator := event.IDGeneratorFunc(func(e event.Event) (string, error) {
+		return "generated-" + e.Type(), nil
+	})
+	for _, tc := range versions {
+		t.Run(tc, func(t *testing.T) {
+			e := event.New(tc)
+			e.SetType("type")
+
+			got := NewDefaultIDIfNotSet(generator)(context.TODO(), e)
+
+			if e.ID() != "" {
+				t.Errorf("modified the original event")
+			}
+			if got.ID() != "generated-type" {
+				t.Errorf("unexpected id %q", got.ID())
+			}
+
+			e.SetID("abc-123")
+			if got := NewDefaultIDIfNotSet(generator)(context.TODO(), e); got.ID() != "abc-123" {
+				t.Errorf("id was defaulted when already set")
+			}
+		})
+	}
+}
+
+func TestNewDefaultIDIfNotSet_error(t *testing.T) {
+	generator := event.IDGeneratorFunc(func(e event.Event) (string, error) {
+		return "", errors.New("generator failure")
+	})
+
+	got := NewDefaultIDIfNotSet(generator)(context.TODO(), event.New())
+
+	if got.ID() != "" {
+		t.Errorf("unexpected id %q", got.ID())
+	}
+}
+
 func TestDefaultTimeToNowIfNotSet_empty(t *testing.T) {
 	for _, tc := range versions {
 		t.Run(tc, func(t *testing.T) {
@@ -0,0 +1,65 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// OutboundEventInterceptor is the function signature for extensions that are able
+// to modify an outgoing event after the defaulter chain has been applied.
+// Unlike an EventDefaulter, an OutboundEventInterceptor can fail the send.
+type OutboundEventInterceptor func(ctx context.Context, event *event.Event) error
+
+// InboundEventInterceptor is the function signature for extensions that are able
+// to inspect and modify a received event before it is passed to the receiver function.
+// The returned context is passed to the next interceptor and then to the receiver function.
+// If the returned error is not nil, the receiver function is not invoked and the error is
+// used as protocol.Result for the message: return protocol.ResultACK to drop the event
+// while acknowledging it.
+type InboundEventInterceptor func(ctx context.Context, event *event.Event) (context.Context, error)
+
+type malformedEventError struct {
+	err error
+}
+
+func (m *malformedEventError) Error() string {
+	return m.err.Error()
+}
+
+func (m *malformedEventError) Unwrap() error {
+	return m.err
+}
+
+// NewMalformedEventResult returns a NACK result that an InboundEventInterceptor can return
+// to reject a malformed or invalid event. The client records the provided error through
+// ObservabilityService.RecordReceivedMalformedEvent.
+func NewMalformedEventResult(err error) protocol.Result {
+	return protocol.NewReceipt(false, "malformed event: %w", &malformedEventError{err: err})
+}
+
+type invocationDeadlineKey struct{}
+
+// WithInvocationDeadline returns a context requesting the client to cancel the context of the receiver function
+// at deadline. The client derives the context with the deadline once the inbound interceptor chain is applied,
+// and releases it when the invocation completes: an InboundEventInterceptor can't release the contexts it returns.
+// If several deadlines are requested, the earliest one applies.
+func WithInvocationDeadline(ctx context.Context, deadline time.Time) context.Context {
+	if d, ok := InvocationDeadlineFrom(ctx); ok && d.Before(deadline) {
+		return ctx
+	}
+	return context.WithValue(ctx, invocationDeadlineKey{}, deadline)
+}
+
+// InvocationDeadlineFrom returns the deadline requested through WithInvocationDeadline, if any.
+func InvocationDeadlineFrom(ctx context.Context) (time.Time, bool) {
+	d, ok := ctx.Value(invocationDeadlineKey{}).(time.Time)
+	return d, ok
+}
@@ -7,7 +7,9 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
@@ -23,11 +25,23 @@ type Invoker interface {
 
 var _ Invoker = (*receiveInvoker)(nil)
 
-func newReceiveInvoker(fn interface{}, observabilityService ObservabilityService, inboundContextDecorators []func(context.Context, binding.Message) context.Context, fns ...EventDefaulter) (Invoker, error) {
+// receiveInvokerConfig configures a receiveInvoker
+type receiveInvokerConfig struct {
+	observabilityService     ObservabilityService
+	eventDefaulterFns        []EventDefaulter
+	inboundContextDecorators []func(context.Context, binding.Message) context.Context
+	inboundInterceptors      []InboundEventInterceptor
+	outboundInterceptors     []OutboundEventInterceptor
+	// inboundValidation and outboundValidation validate the received events and the response events
+	inboundValidation  ValidationMode
+	outboundValidation ValidationMode
+	// pooledEvents converts the messages with binding.ToPooledEvent
+	pooledEvents bool
+}
+
+func newReceiveInvoker(fn interface{}, config receiveInvokerConfig) (Invoker, error) {
 	r := &receiveInvoker{
-		eventDefaulterFns:        fns,
-		observabilityService:     observabilityService,
-		inboundContextDecorators: inboundContextDecorators,
+		receiveInvokerConfig: config,
 	}
 
 	if fn, err := receiver(fn); err != nil {
@@ -35,15 +49,17 @@ func newReceiveInvoker(fn interface{}, observabilityService ObservabilityService
 	} else {
 		r.fn = fn
 	}
+	if r.fn.hasDataIn && len(r.inboundInterceptors) > 0 {
+		// The streamed event has no data, which the interceptors might verify or replace
+		return nil, errors.New("streaming receiver callback can not be used with inbound event interceptors")
+	}
 
 	return r, nil
 }
 
 type receiveInvoker struct {
-	fn                       *receiverFn
-	observabilityService     ObservabilityService
-	eventDefaulterFns        []EventDefaulter
-	inboundContextDecorators []func(context.Context, binding.Message) context.Context
+	receiveInvokerConfig
+	fn *receiverFn
 }
 
 func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn protocol.ResponseFn) (err error) {
@@ -54,7 +70,22 @@ func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn p
 	var respMsg binding.Message
 	var result protocol.Result
 
-	e, eventErr := binding.ToEvent(ctx, m)
+	var e *event.Event
+	var data io.Reader
+	var release func()
+	var eventErr error
+	switch {
+	case r.fn.hasDataIn:
+		e, data, eventErr = binding.ToEventStream(ctx, m)
+	case r.pooledEvents:
+		e, release, eventErr = binding.ToPooledEvent(ctx, m)
+	default:
+		e, eventErr = binding.ToEvent(ctx, m)
+	}
+	if release != nil {
+		// The pooled event might reference the buffers of m, so it's released before finishing m
+		defer release()
+	}
 	switch {
 	case eventErr != nil && r.fn.hasEventIn:
 		r.observabilityService.RecordReceivedMalformedEvent(ctx, eventErr)
@@ -62,12 +93,41 @@ func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn p
 	case r.fn != nil:
 		// Check if event is valid before invoking the receiver function
 		if e != nil {
-			if validationErr := e.Validate(); validationErr != nil {
+			if validationErr := validateEvent(ctx, r.inboundValidation, r.observabilityService, e); validationErr != nil {
 				r.observabilityService.RecordReceivedMalformedEvent(ctx, validationErr)
 				return respFn(ctx, nil, protocol.NewReceipt(false, "validation error in incoming event: %w", validationErr))
 			}
 		}
 
+		ctx = computeInboundContext(m, ctx, r.inboundContextDecorators)
+
+		// Contexts derived by interceptors are released when the invocation completes
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		// Apply the inbound interceptor chain, which can stop the processing of the event
+		if e != nil {
+			for _, fn := range r.inboundInterceptors {
+				var interceptErr error
+				if ctx, interceptErr = fn(ctx, e); interceptErr != nil {
+					var malformed *malformedEventError
+					if errors.As(interceptErr, &malformed) {
+						r.observabilityService.RecordReceivedMalformedEvent(ctx, malformed.err)
+					}
+					if respFn == nil {
+						return interceptErr
+					}
+					return respFn(ctx, nil, interceptErr)
+				}
+			}
+		}
+		if deadline, ok := InvocationDeadlineFrom(ctx); ok {
+			var cancelDeadline context.CancelFunc
+			ctx, cancelDeadline = context.WithDeadline(ctx, deadline)
+			defer cancelDeadline()
+		}
+
 		// Let's invoke the receiver fn
 		var resp *event.Event
 		resp, result = func() (resp *event.Event, result protocol.Result) {
@@ -77,12 +137,11 @@ func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn p
 					cecontext.LoggerFrom(ctx).Error(result)
 				}
 			}()
-			ctx = computeInboundContext(m, ctx, r.inboundContextDecorators)
 
 			var cb func(error)
 			ctx, cb = r.observabilityService.RecordCallingInvoker(ctx, e)
 
-			resp, result = r.fn.invoke(ctx, e)
+			resp, result = r.fn.invoke(ctx, e, data)
 			defer cb(result)
 			return
 		}()
@@ -96,8 +155,19 @@ func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn p
 			for _, fn := range r.eventDefaulterFns {
 				*resp = fn(ctx, *resp)
 			}
+		}
+		if resp != nil && len(r.outboundInterceptors) > 0 {
+			for _, fn := range r.outboundInterceptors {
+				if iErr := fn(ctx, resp); iErr != nil {
+					cecontext.LoggerFrom(ctx).Errorf("outbound interceptor failed on response event: %v", iErr)
+					resp = nil
+					break
+				}
+			}
+		}
+		if resp != nil && (len(r.eventDefaulterFns) > 0 || len(r.outboundInterceptors) > 0) {
 			// Validate the event conforms to the CloudEvents Spec.
-			if vErr := resp.Validate(); vErr != nil {
+			if vErr := validateEvent(ctx, r.outboundValidation, r.observabilityService, resp); vErr != nil {
 				cecontext.LoggerFrom(ctx).Errorf("cloudevent validation failed on response event: %v", vErr)
 			}
 		}
@@ -127,7 +197,7 @@ func (r *receiveInvoker) IsResponder() bool {
 
 func computeInboundContext(message binding.Message, fallback context.Context, inboundContextDecorators []func(context.Context, binding.Message) context.Context) context.Context {
 	result := fallback
-	if mctx, ok := message.(binding.MessageContext); ok {
+	if mctx, ok := message.(binding.MessageContext); ok && mctx.Context() != nil {
 		result = mctx.Context()
 	}
 	for _, f := range inboundContextDecorators {
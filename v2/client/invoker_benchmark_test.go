@@ -0,0 +1,51 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func benchmarkReceiveInvoker(b *testing.B, pooledEvents bool) {
+	e := test.MinEvent()
+	e.SetSubject("subject")
+	e.SetExtension("exstring", "value")
+	m := bindingtest.MustCreateMockBinaryMessage(e)
+
+	var id string
+	invoker, err := newReceiveInvoker(func(e event.Event) {
+		id = e.ID()
+	}, receiveInvokerConfig{observabilityService: noopObservabilityService{}, pooledEvents: pooledEvents})
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := invoker.Invoke(ctx, m, nil); !protocol.IsACK(err) {
+			b.Fatal(err)
+		}
+	}
+	if id != e.ID() {
+		b.Fatalf("unexpected id %q", id)
+	}
+}
+
+func BenchmarkReceiveInvoker_Invoke(b *testing.B) {
+	benchmarkReceiveInvoker(b, false)
+}
+
+// BenchmarkReceiveInvoker_InvokePooled validates the pooled events without parsing their attributes
+func BenchmarkReceiveInvoker_InvokePooled(b *testing.B) {
+	benchmarkReceiveInvoker(b, true)
+}
@@ -33,6 +33,13 @@ type ObservabilityService interface {
 	RecordRequestEvent(ctx context.Context, event event.Event) (context.Context, func(errOrResult error, event *event.Event))
 }
 
+// RejectionRecorder can be implemented by an ObservabilityService to record the requests rejected by the protocol
+// before they reach the client, e.g. because their sender exceeded the allowed rate, see http.WithRejectionObserver.
+type RejectionRecorder interface {
+	// RecordRejectedRequest is invoked with the reason of the rejection of a request.
+	RecordRejectedRequest(ctx context.Context, err error)
+}
+
 type noopObservabilityService struct{}
 
 func (n noopObservabilityService) InboundContextDecorators() []func(context.Context, binding.Message) context.Context {
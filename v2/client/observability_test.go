@@ -0,0 +1,91 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+type rejectingTestReceiver struct {
+	limitsTestReceiver
+	rejections chan error
+}
+
+func (r rejectingTestReceiver) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case err := <-r.rejections:
+		return nil, err
+	default:
+	}
+	return r.limitsTestReceiver.Receive(ctx)
+}
+
+type malformedTestObservabilityService struct {
+	noopObservabilityService
+	malformed chan error
+}
+
+func (o malformedTestObservabilityService) RecordReceivedMalformedEvent(ctx context.Context, err error) {
+	o.malformed <- err
+}
+
+func TestProtocolRejectionsRecorded(t *testing.T) {
+	receiver := rejectingTestReceiver{limitsTestReceiver: make(limitsTestReceiver), rejections: make(chan error, 1)}
+	obs := malformedTestObservabilityService{malformed: make(chan error, 1)}
+	c, err := New(receiver, WithObservabilityService(obs), WithPollGoroutines(1))
+	require.NoError(t, err)
+
+	rejection := protocol.NewReceipt(false, "rate exceeded")
+	receiver.rejections <- rejection
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.StartReceiver(ctx, func(e event.Event) {})
+	}()
+
+	require.Equal(t, rejection, <-obs.malformed)
+}
+
+type rejectionTestObservabilityService struct {
+	noopObservabilityService
+	rejected chan error
+}
+
+func (o rejectionTestObservabilityService) RecordRejectedRequest(ctx context.Context, err error) {
+	o.rejected <- err
+}
+
+func TestHTTPRejectionsRecorded(t *testing.T) {
+	p, err := http.New()
+	require.NoError(t, err)
+	p.WebhookConfig = &http.WebhookConfig{AllowedOrigins: []string{"https://sender.example.com"}}
+	obs := rejectionTestObservabilityService{rejected: make(chan error, 1)}
+	_, err = New(p, WithObservabilityService(obs))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(p)
+	defer server.Close()
+	req, err := nethttp.NewRequest(nethttp.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("WebHook-Request-Origin", "https://evil.example.com")
+	resp, err := nethttp.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, nethttp.StatusForbidden, resp.StatusCode)
+
+	require.True(t, errors.Is(<-obs.rejected, http.ErrOriginNotAllowed))
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 )
 
 // Option is the function signature required to be considered an client.Option.
@@ -56,6 +57,20 @@ func WithUUIDs() Option {
 	}
 }
 
+// WithIDGenerator adds an event defaulter assigning the ids generated by generator,
+// e.g. event.UUIDv7IDGenerator, to the events without id, to the end of the defaulter chain.
+func WithIDGenerator(generator event.IDGenerator) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if generator == nil {
+				return fmt.Errorf("client option was given an nil id generator")
+			}
+			c.eventDefaulterFns = append(c.eventDefaulterFns, NewDefaultIDIfNotSet(generator))
+		}
+		return nil
+	}
+}
+
 // WithTimeNow adds DefaultTimeToNowIfNotSet event defaulter to the end of the
 // defaulter chain.
 func WithTimeNow() Option {
@@ -113,3 +128,82 @@ func WithInboundContextDecorator(dec func(context.Context, binding.Message) cont
 		return nil
 	}
 }
+
+// WithOutboundEventInterceptor adds an outbound event interceptor to the end of
+// the interceptor chain. Outbound interceptors are applied to sent events and to
+// response events, after the defaulter chain.
+func WithOutboundEventInterceptor(fn OutboundEventInterceptor) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if fn == nil {
+				return fmt.Errorf("client option was given an nil outbound event interceptor")
+			}
+			c.outboundEventInterceptors = append(c.outboundEventInterceptors, fn)
+		}
+		return nil
+	}
+}
+
+// WithInboundEventInterceptor adds an inbound event interceptor to the end of
+// the interceptor chain. Inbound interceptors are applied to received events,
+// after validation and before invoking the receiver function. They can't be used with
+// a streaming receiver function, which receives the data as an io.Reader.
+func WithInboundEventInterceptor(fn InboundEventInterceptor) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if fn == nil {
+				return fmt.Errorf("client option was given an nil inbound event interceptor")
+			}
+			c.inboundEventInterceptors = append(c.inboundEventInterceptors, fn)
+		}
+		return nil
+	}
+}
+
+// WithLimits bounds the size, the number of extensions and the length of the extension values
+// of the received events. Messages exceeding the limits are NACKed and recorded as malformed events
+// without invoking the receiver function.
+func WithLimits(limits binding.Limits) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			c.limits = &limits
+		}
+		return nil
+	}
+}
+
+// WithInboundValidation sets how the received events are validated before being passed to the receiver function.
+// Defaults to ValidationDefault.
+func WithInboundValidation(mode ValidationMode) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			c.inboundValidation = mode
+		}
+		return nil
+	}
+}
+
+// WithOutboundValidation sets how the sent and requested events, and the events responded by the receiver function,
+// are validated. Defaults to ValidationDefault.
+func WithOutboundValidation(mode ValidationMode) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			c.outboundValidation = mode
+		}
+		return nil
+	}
+}
+
+// WithPooledEvents makes the receiver reuse the received events and their buffers, using binding.ToPooledEvent,
+// to reduce the allocations on high throughput receive paths. The attributes are parsed when first accessed.
+// The event passed to the receiver function, its extensions and its data are valid only until the function returns:
+// they must not be retained, nor used by goroutines outliving the function, e.g. sending the event asynchronously.
+// Clone the event with event.Clone() to keep it.
+func WithPooledEvents() Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			c.pooledEvents = true
+		}
+		return nil
+	}
+}
@@ -7,9 +7,17 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -109,6 +117,16 @@ func TestWith_Defaulters(t *testing.T) {
 			opts: []Option{WithUUIDs(), WithTimeNow()},
 			want: 2,
 		},
+		"id generator": {
+			c:    &ceClient{},
+			opts: []Option{WithIDGenerator(event.UUIDv7IDGenerator)},
+			want: 1,
+		},
+		"nil id generator": {
+			c:       &ceClient{},
+			opts:    []Option{WithIDGenerator(nil)},
+			wantErr: "client option was given an nil id generator",
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -136,3 +154,175 @@ func TestWith_Defaulters(t *testing.T) {
 		})
 	}
 }
+
+func TestWith_EventInterceptors(t *testing.T) {
+	outbound := func(ctx context.Context, event *event.Event) error {
+		return nil
+	}
+	inbound := func(ctx context.Context, event *event.Event) (context.Context, error) {
+		return ctx, nil
+	}
+
+	c := &ceClient{}
+	if err := c.applyOptions(WithOutboundEventInterceptor(outbound), WithInboundEventInterceptor(inbound), WithInboundEventInterceptor(inbound)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(1, len(c.outboundEventInterceptors)); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(2, len(c.inboundEventInterceptors)); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+
+	err := c.applyOptions(WithOutboundEventInterceptor(nil))
+	if diff := cmp.Diff("client option was given an nil outbound event interceptor", err.Error()); diff != "" {
+		t.Errorf("unexpected error (-want, +got) = %v", diff)
+	}
+	err = c.applyOptions(WithInboundEventInterceptor(nil))
+	if diff := cmp.Diff("client option was given an nil inbound event interceptor", err.Error()); diff != "" {
+		t.Errorf("unexpected error (-want, +got) = %v", diff)
+	}
+}
+
+func TestWithInvocationDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	inbound := func(ctx context.Context, event *event.Event) (context.Context, error) {
+		ctx = WithInvocationDeadline(ctx, deadline.Add(time.Minute))
+		return WithInvocationDeadline(ctx, deadline), nil
+	}
+
+	receiver := make(limitsTestReceiver)
+	c, err := New(receiver, WithInboundEventInterceptor(inbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := make(chan context.Context, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) {
+			received <- ctx
+		})
+	}()
+
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	results := make(chan error)
+	receiver <- binding.WithFinish(bindingtest.MustCreateMockBinaryMessage(e), func(err error) {
+		results <- err
+	})
+	if result := <-results; !protocol.IsACK(result) {
+		t.Errorf("expected ACK, got %v", result)
+	}
+
+	invocationCtx := <-received
+	got, ok := invocationCtx.Deadline()
+	if !ok || !got.Equal(deadline) {
+		t.Errorf("expected deadline %v, got %v", deadline, got)
+	}
+	// The deadline is released once the invocation completes
+	if invocationCtx.Err() == nil {
+		t.Errorf("expected the invocation context to be done")
+	}
+}
+
+type limitsTestReceiver chan binding.Message
+
+func (r limitsTestReceiver) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m, ok := <-r:
+		if !ok {
+			return nil, io.EOF
+		}
+		return m, nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	receiver := make(limitsTestReceiver)
+	c, err := New(receiver, WithLimits(binding.Limits{MaxEventSize: 100}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := make(chan event.Event, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.StartReceiver(ctx, func(e event.Event) {
+			received <- e
+		})
+	}()
+
+	results := make(chan error)
+	for _, size := range []int{10, 1000} {
+		e := event.New()
+		e.SetID("id")
+		e.SetType("type")
+		e.SetSource("source")
+		_ = e.SetData(event.TextPlain, strings.Repeat("a", size))
+		receiver <- binding.WithFinish(bindingtest.MustCreateMockBinaryMessage(e), func(err error) {
+			results <- err
+		})
+
+		result := <-results
+		if size < 100 && !protocol.IsACK(result) {
+			t.Errorf("expected ACK, got %v", result)
+		}
+		if size > 100 && (!protocol.IsNACK(result) || !errors.Is(result, binding.ErrLimitExceeded)) {
+			t.Errorf("expected NACK for exceeded limit, got %v", result)
+		}
+	}
+	if diff := cmp.Diff(1, len(received)); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+func TestWithPooledEvents(t *testing.T) {
+	receiver := make(limitsTestReceiver)
+	c, err := New(receiver, WithPooledEvents())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	received := make(chan string, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.StartReceiver(ctx, func(e event.Event) {
+			// Copy the values, since the event is reused after this function returns
+			received <- e.ID() + ":" + string(e.Data()) + ":" + fmt.Sprint(len(e.Extensions()))
+		})
+	}()
+
+	results := make(chan error)
+	for i := 0; i < 3; i++ {
+		e := event.New()
+		e.SetID(fmt.Sprintf("id%d", i))
+		e.SetType("type")
+		e.SetSource("source")
+		if i == 0 {
+			e.SetExtension("exta", "a")
+		}
+		_ = e.SetData(event.TextPlain, strings.Repeat("a", i+1))
+		receiver <- binding.WithFinish(bindingtest.MustCreateMockBinaryMessage(e), func(err error) {
+			results <- err
+		})
+		if result := <-results; !protocol.IsACK(result) {
+			t.Errorf("expected ACK, got %v", result)
+		}
+	}
+
+	close(received)
+	var got []string
+	for s := range received {
+		got = append(got, s)
+	}
+	if diff := cmp.Diff([]string{"id0:a:1", "id1:aa:0", "id2:aaa:0"}, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
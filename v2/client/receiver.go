@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -25,13 +26,14 @@ type receiverFn struct {
 
 	hasContextIn bool
 	hasEventIn   bool
+	hasDataIn    bool
 
 	hasEventOut  bool
 	hasResultOut bool
 }
 
 const (
-	inParamUsage  = "expected a function taking either no parameters, one or more of (context.Context, event.Event) ordered"
+	inParamUsage  = "expected a function taking either no parameters, one or more of (context.Context, event.Event) ordered, or (context.Context, event.Event, io.Reader)"
 	outParamUsage = "expected a function returning one or mode of (*event.Event, protocol.Result) ordered"
 )
 
@@ -39,6 +41,7 @@ var (
 	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
 	eventType    = reflect.TypeOf((*event.Event)(nil)).Elem()
 	eventPtrType = reflect.TypeOf((*event.Event)(nil)) // want the ptr type
+	readerType   = reflect.TypeOf((*io.Reader)(nil)).Elem()
 	resultType   = reflect.TypeOf((*protocol.Result)(nil)).Elem()
 )
 
@@ -57,6 +60,15 @@ var (
 // * func(event.Event) (*event.Event, protocol.Result)
 // * func(context.Context, event.Event) *event.Event
 // * func(context.Context, event.Event) (*event.Event, protocol.Result)
+// The streaming variants, receiving the data as an io.Reader instead of in the event, are:
+// * func(context.Context, event.Event, io.Reader)
+// * func(context.Context, event.Event, io.Reader) protocol.Result
+// * func(context.Context, event.Event, io.Reader) *event.Event
+// * func(context.Context, event.Event, io.Reader) (*event.Event, protocol.Result)
+// The data is streamed only from the binary messages, like the HTTP requests in binary mode, and it's read in memory
+// otherwise: the WebSocket messages, which are structured, stay buffered. The streaming variants can't be used with
+// inbound event interceptors, which would see an event without data: a signature verification, a decryption or
+// the data retrieved from a data reference wouldn't apply to the data of the receiver function.
 //
 func receiver(fn interface{}) (*receiverFn, error) {
 	fnType := reflect.TypeOf(fn)
@@ -77,7 +89,7 @@ func receiver(fn interface{}) (*receiverFn, error) {
 	return r, nil
 }
 
-func (r *receiverFn) invoke(ctx context.Context, e *event.Event) (*event.Event, protocol.Result) {
+func (r *receiverFn) invoke(ctx context.Context, e *event.Event, data io.Reader) (*event.Event, protocol.Result) {
 	args := make([]reflect.Value, 0, r.numIn)
 
 	if r.numIn > 0 {
@@ -87,6 +99,9 @@ func (r *receiverFn) invoke(ctx context.Context, e *event.Event) (*event.Event,
 		if r.hasEventIn {
 			args = append(args, reflect.ValueOf(*e))
 		}
+		if r.hasDataIn {
+			args = append(args, reflect.ValueOf(&data).Elem())
+		}
 	}
 	v := r.fnValue.Call(args)
 	var respOut protocol.Result
@@ -110,12 +125,24 @@ func (r *receiverFn) invoke(ctx context.Context, e *event.Event) (*event.Event,
 
 // Verifies that the inputs to a function have a valid signature
 // Valid input is to be [0, all] of
-// context.Context, event.Event in this order.
+// context.Context, event.Event in this order,
+// or context.Context, event.Event, io.Reader.
 func (r *receiverFn) validateInParamSignature(fnType reflect.Type) error {
 	r.hasContextIn = false
 	r.hasEventIn = false
+	r.hasDataIn = false
 
 	switch fnType.NumIn() {
+	case 3:
+		// has to be (context.Context, event.Event, io.Reader)
+		if !readerType.ConvertibleTo(fnType.In(2)) {
+			return fmt.Errorf("%s; cannot convert parameter 3 to %s from io.Reader", inParamUsage, fnType.In(2))
+		}
+		if !contextType.ConvertibleTo(fnType.In(0)) {
+			return fmt.Errorf("%s; cannot convert parameter 1 to %s from context.Context", inParamUsage, fnType.In(0))
+		}
+		r.hasDataIn = true
+		fallthrough
 	case 2:
 		// has to be (context.Context, event.Event)
 		if !eventType.ConvertibleTo(fnType.In(1)) {
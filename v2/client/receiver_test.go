@@ -8,6 +8,7 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -45,6 +46,10 @@ func TestReceiverFnValidTypes(t *testing.T) {
 		"Event in, Event+Result out":     func(event.Event) (*event.Event, protocol.Result) { return nil, nil },
 		"ctx+Event in, Event+Result out": func(context.Context, event.Event) (*event.Event, protocol.Result) { return nil, nil },
 
+		"ctx+Event+Reader in, no out":           func(context.Context, event.Event, io.Reader) {},
+		"ctx+Event+Reader in, error out":        func(context.Context, event.Event, io.Reader) error { return nil },
+		"ctx+Event+Reader in, Event+Result out": func(context.Context, event.Event, io.Reader) (*event.Event, protocol.Result) { return nil, nil },
+
 		"input contravariance; may accept supertype": func(event.EventReader) {},
 		"output covariance; may return subtype":      func() *myErr { return nil },
 	} {
@@ -75,6 +80,9 @@ func TestReceiverFnInvalidTypes(t *testing.T) {
 		"extra Event in":           func(event.Event, event.Event) {},
 		"not a function":           map[string]string(nil),
 
+		"Reader without context in": func(event.Event, event.Event, io.Reader) {},
+		"Reader as first param in":  func(io.Reader, event.Event, context.Context) {},
+
 		"input covariance; must not accept subtype": func(*myCtx) {},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -111,7 +119,7 @@ func TestReceiverFnInvoke_1(t *testing.T) {
 		t.Errorf("unexpected error, wanted nil got = %v", err)
 	}
 
-	resp, result := fn.invoke(wantCtx, &wantEvent)
+	resp, result := fn.invoke(wantCtx, &wantEvent, nil)
 
 	if diff := cmp.Diff(wantResp, resp); diff != "" {
 		t.Errorf("unexpected response (-want, +got) = %v", diff)
@@ -145,7 +153,7 @@ func TestReceiverFnInvoke_2(t *testing.T) {
 		t.Errorf("unexpected error, wanted nil got = %v", err)
 	}
 
-	resp, result := fn.invoke(ctx, &wantEvent)
+	resp, result := fn.invoke(ctx, &wantEvent, nil)
 
 	if diff := cmp.Diff(wantResp, resp); diff != "" {
 		t.Errorf("unexpected response (-want, +got) = %v", diff)
@@ -179,7 +187,7 @@ func TestReceiverFnInvoke_3(t *testing.T) {
 		t.Errorf("unexpected error, wanted nil got = %v", err)
 	}
 
-	resp, result := fn.invoke(ctx, &wantEvent)
+	resp, result := fn.invoke(ctx, &wantEvent, nil)
 
 	if diff := cmp.Diff(wantResp, resp); diff != "" {
 		t.Errorf("unexpected response (-want, +got) = %v", diff)
@@ -205,7 +213,7 @@ func TestReceiverFnInvoke_4(t *testing.T) {
 		t.Errorf("unexpected error, wanted nil got = %v", err)
 	}
 
-	resp, result := fn.invoke(ctx, &event.Event{})
+	resp, result := fn.invoke(ctx, &event.Event{}, nil)
 
 	if diff := cmp.Diff(wantResp, resp); diff != "" {
 		t.Errorf("unexpected response (-want, +got) = %v", diff)
@@ -230,7 +238,7 @@ func TestReceiverFnInvoke_5(t *testing.T) {
 		t.Errorf("unexpected error, wanted nil got = %v", err)
 	}
 
-	resp, result := fn.invoke(ctx, &event.Event{})
+	resp, result := fn.invoke(ctx, &event.Event{}, nil)
 
 	if diff := cmp.Diff(wantResp, resp); diff != "" {
 		t.Errorf("unexpected response (-want, +got) = %v", diff)
@@ -0,0 +1,71 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// ValidationMode selects how the client validates the events it sends and receives.
+type ValidationMode int
+
+const (
+	// ValidationDefault rejects the events failing event.Event.Validate.
+	ValidationDefault ValidationMode = iota
+	// ValidationStrict rejects the events failing event.Event.ValidateStrict, which enforces precisely the spec 1.0.2 rules.
+	// The events not following the recommendations of the spec, see event.Event.ValidationWarnings, are accepted
+	// and reported as warnings through the logger and the ObservabilityService.
+	ValidationStrict
+	// ValidationLenient accepts the events failing event.Event.ValidateStrict, reporting the violations as warnings
+	// through the logger and the ObservabilityService. This mode is meant for legacy producers.
+	// Events without a context are still rejected.
+	ValidationLenient
+)
+
+// ValidationWarningRecorder can be implemented by an ObservabilityService to record the validation warnings
+// of the events accepted by ValidationStrict and ValidationLenient.
+type ValidationWarningRecorder interface {
+	// RecordValidationWarning is invoked when an event failing the validation, or not following the
+	// recommendations of the spec, is accepted.
+	RecordValidationWarning(ctx context.Context, event *event.Event, err error)
+}
+
+// validateEvent validates e according to mode, returning an error if e must be rejected
+func validateEvent(ctx context.Context, mode ValidationMode, observabilityService ObservabilityService, e *event.Event) error {
+	switch mode {
+	case ValidationStrict:
+		if err := e.ValidateStrict(); err != nil {
+			return err
+		}
+		reportValidationWarning(ctx, observabilityService, e, e.ValidationWarnings())
+		return nil
+	case ValidationLenient:
+		if e.Context == nil {
+			return e.Validate()
+		}
+		reportValidationWarning(ctx, observabilityService, e, e.ValidateStrict())
+		reportValidationWarning(ctx, observabilityService, e, e.ValidationWarnings())
+		return nil
+	default:
+		return e.Validate()
+	}
+}
+
+// reportValidationWarning logs and records err, if not nil, as a validation warning of the accepted event e
+func reportValidationWarning(ctx context.Context, observabilityService ObservabilityService, e *event.Event, err error) {
+	if err == nil {
+		return
+	}
+	cecontext.LoggerFrom(ctx).Warnw("accepting an event with validation warnings", zap.String("id", e.ID()), zap.Error(err))
+	if r, ok := observabilityService.(ValidationWarningRecorder); ok {
+		r.RecordValidationWarning(ctx, e, err)
+	}
+}
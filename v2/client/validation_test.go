@@ -0,0 +1,137 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type validationTestObservabilityService struct {
+	noopObservabilityService
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (o *validationTestObservabilityService) RecordValidationWarning(ctx context.Context, e *event.Event, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.warnings = append(o.warnings, e.ID())
+}
+
+type validationTestSender struct {
+	sent int
+}
+
+func (s *validationTestSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	s.sent++
+	return m.Finish(nil)
+}
+
+// strictInvalidEvent returns an event failing only the strict validation
+func strictInvalidEvent() event.Event {
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	// The extension names MUST be lower-case
+	e.Context.(*event.EventContextV1).Extensions = map[string]interface{}{"myExt": "a"}
+	return e
+}
+
+func TestOutboundValidation(t *testing.T) {
+	for _, tc := range []struct {
+		mode         ValidationMode
+		wantErr      bool
+		wantWarnings int
+	}{
+		{mode: ValidationDefault},
+		{mode: ValidationStrict, wantErr: true},
+		{mode: ValidationLenient, wantWarnings: 1},
+	} {
+		sender := &validationTestSender{}
+		obs := &validationTestObservabilityService{}
+		c, err := New(sender, WithOutboundValidation(tc.mode), WithObservabilityService(obs))
+		require.NoError(t, err)
+
+		result := c.Send(context.Background(), strictInvalidEvent())
+		if tc.wantErr {
+			require.Error(t, result)
+			require.Equal(t, 0, sender.sent)
+		} else {
+			require.True(t, protocol.IsACK(result))
+			require.Equal(t, 1, sender.sent)
+		}
+		require.Len(t, obs.warnings, tc.wantWarnings)
+
+		// Events without a context are always rejected
+		require.Error(t, c.Send(context.Background(), event.Event{}))
+	}
+}
+
+func TestValidationWarnings(t *testing.T) {
+	for _, mode := range []ValidationMode{ValidationDefault, ValidationStrict, ValidationLenient} {
+		sender := &validationTestSender{}
+		obs := &validationTestObservabilityService{}
+		c, err := New(sender, WithOutboundValidation(mode), WithObservabilityService(obs))
+		require.NoError(t, err)
+
+		// The extension names SHOULD NOT exceed 20 characters
+		e := event.New()
+		e.SetID("id")
+		e.SetType("type")
+		e.SetSource("source")
+		e.SetExtension("averyveryverylongextension", "a")
+		require.True(t, protocol.IsACK(c.Send(context.Background(), e)))
+		if mode == ValidationDefault {
+			require.Empty(t, obs.warnings)
+		} else {
+			require.Equal(t, []string{"id"}, obs.warnings)
+		}
+	}
+}
+
+func TestInboundValidation(t *testing.T) {
+	for _, tc := range []struct {
+		mode         ValidationMode
+		wantACK      bool
+		wantWarnings int
+	}{
+		{mode: ValidationDefault, wantACK: true},
+		{mode: ValidationStrict},
+		{mode: ValidationLenient, wantACK: true, wantWarnings: 1},
+	} {
+		receiver := make(limitsTestReceiver)
+		obs := &validationTestObservabilityService{}
+		c, err := New(receiver, WithInboundValidation(tc.mode), WithObservabilityService(obs))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			_ = c.StartReceiver(ctx, func(e event.Event) {})
+		}()
+
+		results := make(chan error)
+		e := strictInvalidEvent()
+		receiver <- binding.WithFinish(binding.ToMessage(&e), func(err error) {
+			results <- err
+		})
+		result := <-results
+		cancel()
+
+		require.Equal(t, tc.wantACK, protocol.IsACK(result), "unexpected result %v", result)
+		obs.mu.Lock()
+		require.Len(t, obs.warnings, tc.wantWarnings)
+		obs.mu.Unlock()
+	}
+}
@@ -92,6 +92,30 @@ func WithRetriesExponentialBackoff(ctx context.Context, period time.Duration, ma
 	})
 }
 
+// WithRetriesFullJitterBackoff returns back a new context with retries parameters using full jitter backoff strategy.
+// MaxTries is the maximum number for retries and the time interval between retries is random between 0 and
+// `period * 2^retries`, capped by maxPeriod if positive.
+func WithRetriesFullJitterBackoff(ctx context.Context, period, maxPeriod time.Duration, maxTries int) context.Context {
+	return WithRetryParams(ctx, &RetryParams{
+		Strategy:  BackoffStrategyFullJitter,
+		Period:    period,
+		MaxPeriod: maxPeriod,
+		MaxTries:  maxTries,
+	})
+}
+
+// WithRetriesDecorrelatedJitterBackoff returns back a new context with retries parameters using decorrelated jitter
+// backoff strategy. MaxTries is the maximum number for retries and the time interval between retries is random
+// between period and 3 times the previous interval, capped by maxPeriod if positive.
+func WithRetriesDecorrelatedJitterBackoff(ctx context.Context, period, maxPeriod time.Duration, maxTries int) context.Context {
+	return WithRetryParams(ctx, &RetryParams{
+		Strategy:  BackoffStrategyDecorrelatedJitter,
+		Period:    period,
+		MaxPeriod: maxPeriod,
+		MaxTries:  maxTries,
+	})
+}
+
 // WithRetryParams returns back a new context with retries parameters.
 func WithRetryParams(ctx context.Context, rp *RetryParams) context.Context {
 	return context.WithValue(ctx, retriesKey, rp)
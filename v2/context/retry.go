@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -19,10 +20,19 @@ const (
 	BackoffStrategyConstant    = "constant"
 	BackoffStrategyLinear      = "linear"
 	BackoffStrategyExponential = "exponential"
+	// BackoffStrategyFullJitter waits a random delay between 0 and the exponential backoff,
+	// so the retries of many senders failing at the same time don't line up
+	BackoffStrategyFullJitter = "fulljitter"
+	// BackoffStrategyDecorrelatedJitter waits a random delay between Period and 3 times the previous delay
+	BackoffStrategyDecorrelatedJitter = "decorrelatedjitter"
 )
 
 var DefaultRetryParams = RetryParams{Strategy: BackoffStrategyNone}
 
+// DefaultMaxRetryAfter caps the delay requested by the receiver before retrying when neither MaxRetryAfter
+// nor MaxPeriod is set
+const DefaultMaxRetryAfter = time.Minute
+
 // RetryParams holds parameters applied to retries
 type RetryParams struct {
 	// Strategy is the backoff strategy to applies between retries
@@ -36,7 +46,20 @@ type RetryParams struct {
 	// - for constant strategy: the delay interval between retries
 	// - for linear strategy: interval between retries = Period * retries
 	// - for exponential strategy: interval between retries = Period * retries^2
+	// - for full jitter strategy: interval between retries = random between 0 and Period * retries^2
+	// - for decorrelated jitter strategy: interval between retries = random between Period and 3 * the previous interval
 	Period time.Duration
+
+	// MaxPeriod, if positive, caps the interval between retries computed by the jitter strategies
+	MaxPeriod time.Duration
+
+	// MaxRetryAfter caps the delay requested by the receiver before retrying, for the protocols supporting it,
+	// e.g. the Retry-After header of HTTP. The requested delay replaces the strategy interval.
+	// If not positive, MaxPeriod caps the delay, or DefaultMaxRetryAfter if MaxPeriod isn't positive either.
+	MaxRetryAfter time.Duration
+
+	// AttemptTimeout, if positive, is the timeout of each try, separate from the deadline of the whole send
+	AttemptTimeout time.Duration
 }
 
 // BackoffFor tries will return the time duration that should be used for this
@@ -51,6 +74,10 @@ func (r *RetryParams) BackoffFor(tries int) time.Duration {
 	case BackoffStrategyExponential:
 		exp := math.Exp2(float64(tries))
 		return r.Period * time.Duration(exp)
+	case BackoffStrategyFullJitter:
+		return randomDuration(0, r.capPeriod(saturatingExp2(r.Period, tries)))
+	case BackoffStrategyDecorrelatedJitter:
+		return r.NextBackoff(tries, r.Period)
 	case BackoffStrategyNone:
 		fallthrough // default
 	default:
@@ -58,19 +85,93 @@ func (r *RetryParams) BackoffFor(tries int) time.Duration {
 	}
 }
 
+// NextBackoff works like BackoffFor, but the decorrelated jitter strategy computes the duration from the
+// duration previously waited, which the caller tracks across the retries.
+func (r *RetryParams) NextBackoff(tries int, previous time.Duration) time.Duration {
+	if r.Strategy != BackoffStrategyDecorrelatedJitter {
+		return r.BackoffFor(tries)
+	}
+	if previous < r.Period {
+		previous = r.Period
+	}
+	upper := time.Duration(math.MaxInt64)
+	if previous <= math.MaxInt64/3 {
+		upper = previous * 3
+	}
+	upper = r.capPeriod(upper)
+	if upper <= r.Period {
+		return upper
+	}
+	return randomDuration(r.Period, upper)
+}
+
+// RetryAfter returns the delay to wait when the receiver requested to retry after the provided delay,
+// capped by MaxRetryAfter.
+func (r *RetryParams) RetryAfter(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	limit := r.MaxRetryAfter
+	if limit <= 0 {
+		limit = r.MaxPeriod
+	}
+	if limit <= 0 {
+		limit = DefaultMaxRetryAfter
+	}
+	if delay > limit {
+		return limit
+	}
+	return delay
+}
+
+func (r *RetryParams) capPeriod(d time.Duration) time.Duration {
+	if r.MaxPeriod > 0 && d > r.MaxPeriod {
+		return r.MaxPeriod
+	}
+	return d
+}
+
+// saturatingExp2 returns period * 2^tries, or math.MaxInt64 if it overflows
+func saturatingExp2(period time.Duration, tries int) time.Duration {
+	if period <= 0 || tries <= 0 {
+		return period
+	}
+	if tries >= 63 || period > math.MaxInt64>>uint(tries) {
+		return math.MaxInt64
+	}
+	return period << uint(tries)
+}
+
+// randomDuration returns a random duration between min and max, both included. min must not be negative.
+func randomDuration(min, max time.Duration) time.Duration {
+	n := int64(max - min)
+	if n <= 0 {
+		return min
+	}
+	if n == math.MaxInt64 {
+		// n+1 overflows, and the range misses only its upper bound
+		return min + time.Duration(rand.Int63())
+	}
+	return min + time.Duration(rand.Int63n(n+1))
+}
+
 // Backoff is a blocking call to wait for the correct amount of time for the retry.
 // `tries` is assumed to be the number of times the caller has already retried.
 func (r *RetryParams) Backoff(ctx context.Context, tries int) error {
+	return r.BackoffWith(ctx, tries, r.BackoffFor(tries))
+}
+
+// BackoffWith is like Backoff, but waits for the provided delay, e.g. computed with NextBackoff or RetryAfter.
+func (r *RetryParams) BackoffWith(ctx context.Context, tries int, delay time.Duration) error {
 	if tries > r.MaxTries {
 		return errors.New("too many retries")
 	}
-	ticker := time.NewTicker(r.BackoffFor(tries))
+	timer := time.NewTimer(delay)
 	select {
 	case <-ctx.Done():
-		ticker.Stop()
+		timer.Stop()
 		return errors.New("context has been cancelled")
-	case <-ticker.C:
-		ticker.Stop()
+	case <-timer.C:
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ package context
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 )
@@ -39,6 +40,16 @@ func TestRetryParams_Backoff(t *testing.T) {
 			rp:    &RetryParams{Strategy: BackoffStrategyExponential, MaxTries: 10, Period: 1 * time.Nanosecond},
 			tries: 1,
 		},
+		"full jitter 1": {
+			ctx:   context.Background(),
+			rp:    &RetryParams{Strategy: BackoffStrategyFullJitter, MaxTries: 10, Period: 1 * time.Nanosecond},
+			tries: 1,
+		},
+		"decorrelated jitter 1": {
+			ctx:   context.Background(),
+			rp:    &RetryParams{Strategy: BackoffStrategyDecorrelatedJitter, MaxTries: 10, Period: 1 * time.Nanosecond},
+			tries: 1,
+		},
 		"const timeout": {
 			ctx: func() context.Context {
 				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
@@ -117,3 +128,104 @@ func TestRetryParams_BackoffFor(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryParams_Jitter(t *testing.T) {
+	full := &RetryParams{Strategy: BackoffStrategyFullJitter, MaxTries: 10, Period: time.Second, MaxPeriod: 10 * time.Second}
+	decorrelated := &RetryParams{Strategy: BackoffStrategyDecorrelatedJitter, MaxTries: 10, Period: time.Second, MaxPeriod: 10 * time.Second}
+
+	fullValues := map[time.Duration]bool{}
+	var previous time.Duration
+	for i := 0; i < 100; i++ {
+		tries := i%5 + 1
+		got := full.BackoffFor(tries)
+		upper := time.Second << uint(tries)
+		if upper > full.MaxPeriod {
+			upper = full.MaxPeriod
+		}
+		if got < 0 || got > upper {
+			t.Errorf("full jitter BackoffFor(%d) = %v, want between 0 and %v", tries, got, upper)
+		}
+		fullValues[got] = true
+
+		next := decorrelated.NextBackoff(tries, previous)
+		upper = 3 * previous
+		if upper < 3*time.Second {
+			upper = 3 * time.Second
+		}
+		if upper > decorrelated.MaxPeriod {
+			upper = decorrelated.MaxPeriod
+		}
+		if next < time.Second || next > upper {
+			t.Errorf("decorrelated jitter NextBackoff(%d, %v) = %v, want between 1s and %v", tries, previous, next, upper)
+		}
+		previous = next
+	}
+	if len(fullValues) < 10 {
+		t.Errorf("full jitter returned only %d different values", len(fullValues))
+	}
+
+	// Other strategies ignore the previous duration
+	linear := &RetryParams{Strategy: BackoffStrategyLinear, MaxTries: 10, Period: time.Second}
+	if got := linear.NextBackoff(3, time.Hour); got != 3*time.Second {
+		t.Errorf("NextBackoff() = %v, want %v", got, 3*time.Second)
+	}
+}
+
+func TestRetryParams_JitterLargeTries(t *testing.T) {
+	for _, strategy := range []BackoffStrategy{BackoffStrategyFullJitter, BackoffStrategyDecorrelatedJitter} {
+		uncapped := &RetryParams{Strategy: strategy, MaxTries: 100, Period: time.Second}
+		capped := &RetryParams{Strategy: strategy, MaxTries: 100, Period: time.Second, MaxPeriod: time.Minute}
+		for tries := 30; tries <= 100; tries++ {
+			if got := uncapped.BackoffFor(tries); got < 0 {
+				t.Errorf("%s BackoffFor(%d) = %v, want not negative", strategy, tries, got)
+			}
+			if got := capped.BackoffFor(tries); got < 0 || got > time.Minute {
+				t.Errorf("%s BackoffFor(%d) = %v, want between 0 and 1m", strategy, tries, got)
+			}
+			if got := uncapped.NextBackoff(tries, math.MaxInt64); got < time.Second {
+				t.Errorf("%s NextBackoff(%d) = %v, want at least 1s", strategy, tries, got)
+			}
+		}
+	}
+}
+
+func TestRetryParams_RetryAfter(t *testing.T) {
+	tests := map[string]struct {
+		rp    *RetryParams
+		delay time.Duration
+		want  time.Duration
+	}{
+		"default cap": {
+			rp:    &RetryParams{},
+			delay: 24 * time.Hour,
+			want:  DefaultMaxRetryAfter,
+		},
+		"capped by max period": {
+			rp:    &RetryParams{MaxPeriod: 10 * time.Second},
+			delay: time.Hour,
+			want:  10 * time.Second,
+		},
+		"below max": {
+			rp:    &RetryParams{MaxRetryAfter: time.Minute},
+			delay: time.Second,
+			want:  time.Second,
+		},
+		"capped": {
+			rp:    &RetryParams{MaxRetryAfter: time.Minute},
+			delay: time.Hour,
+			want:  time.Minute,
+		},
+		"date in the past": {
+			rp:    &RetryParams{MaxRetryAfter: time.Minute},
+			delay: -time.Hour,
+			want:  0,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.rp.RetryAfter(tc.delay); got != tc.want {
+				t.Errorf("RetryAfter() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -27,7 +27,18 @@ func WriteJson(in *Event, writer io.Writer) error {
 	var isBase64 bool
 
 	// Write the context (without the extensions)
-	switch eventContext := in.Context.(type) {
+	ec := in.Context
+	switch ec.(type) {
+	case nil, *EventContextV03, *EventContextV1:
+	default:
+		// Convert the other implementations of EventContext to their spec version
+		if ec.GetSpecVersion() == CloudEventsVersionV03 {
+			ec = ec.AsV03()
+		} else {
+			ec = ec.AsV1()
+		}
+	}
+	switch eventContext := ec.(type) {
 	case *EventContextV03:
 		// Set a bunch of variables we need later
 		ext = eventContext.Extensions
@@ -218,6 +218,93 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateStrict(t *testing.T) {
+	now := types.Timestamp{Time: time.Now()}
+
+	withContext := func(fn func(ec *event.EventContextV1)) event.Event {
+		ec := MinEventContextV1()
+		fn(ec)
+		return event.Event{Context: ec}
+	}
+
+	testCases := map[string]struct {
+		event event.Event
+		want  []string
+	}{
+		"min valid v1.0": {
+			event: event.Event{
+				Context: MinEventContextV1(),
+			},
+		},
+		"full valid v1.0": {
+			event: event.Event{
+				Context:     FullEventContextV1(now),
+				DataEncoded: []byte(`{"a":"apple","b":"banana"}`),
+			},
+		},
+		"full valid v0.3": {
+			event: event.Event{
+				Context: FullEventContextV03(now),
+			},
+		},
+		"invalid Validate": {
+			event: withContext(func(ec *event.EventContextV1) {
+				ec.ID = ""
+			}),
+			want: []string{"id: MUST be a non-empty string"},
+		},
+		"upper case extension name": {
+			event: withContext(func(ec *event.EventContextV1) {
+				ec.Extensions = map[string]interface{}{"myExt": "a"}
+			}),
+			want: []string{"myExt: CloudEvents attribute names MUST consist of lower-case letters"},
+		},
+		"long extension name": {
+			event: withContext(func(ec *event.EventContextV1) {
+				ec.Extensions = map[string]interface{}{"averyveryverylongextension": "a"}
+			}),
+		},
+		"time out of the RFC 3339 range": {
+			event: withContext(func(ec *event.EventContextV1) {
+				ec.Time = &types.Timestamp{Time: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)}
+			}),
+			want: []string{"time: if present, MUST adhere to the format specified in RFC 3339: year 10000 out of range"},
+		},
+		"relative dataschema": {
+			event: withContext(func(ec *event.EventContextV1) {
+				ec.DataSchema = types.ParseURI("/schema")
+			}),
+			want: []string{"dataschema: if present, MUST be an absolute URI"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.event.ValidateStrict()
+			if len(tc.want) == 0 {
+				require.NoError(t, got)
+				return
+			}
+			require.Error(t, got)
+			for _, want := range tc.want {
+				require.Contains(t, got.Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidationWarnings(t *testing.T) {
+	e := event.Event{Context: MinEventContextV1()}
+	require.NoError(t, e.ValidationWarnings())
+
+	e.SetExtension("averyveryverylongextension", "a")
+	e.SetExtension("short", "a")
+	require.Equal(t, event.ValidationError{
+		"averyveryverylongextension": fmt.Errorf("CloudEvents attribute names SHOULD NOT exceed 20 characters"),
+	}, e.ValidationWarnings())
+
+	require.NoError(t, event.Event{}.ValidationWarnings())
+}
+
 func TestString(t *testing.T) {
 	now := types.Timestamp{Time: time.Now()}
 
@@ -7,9 +7,13 @@ package event
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
+// StrictMaxExtensionNameLength is the length of the extension names above which ValidationWarnings reports them
+const StrictMaxExtensionNameLength = 20
+
 type ValidationError map[string]error
 
 func (e ValidationError) Error() string {
@@ -48,3 +52,85 @@ func (e Event) Validate() error {
 	}
 	return nil
 }
+
+// ValidateStrict performs the same validation as Validate, and also enforces precisely these rules of the spec 1.0.2:
+//   - the extension names MUST consist of lower-case ASCII letters or digits
+//   - source MUST be a non-empty URI-reference
+//   - time, if present, MUST be representable in RFC 3339, so its year must be within 0000 and 9999
+//   - dataschema, if present, MUST be an absolute URI
+//
+// The recommendations of the spec are checked by ValidationWarnings.
+func (e Event) ValidateStrict() error {
+	err := e.Validate()
+	if e.Context == nil {
+		return err
+	}
+
+	errs := ValidationError{}
+	if ve, ok := err.(ValidationError); ok {
+		errs = ve
+	}
+
+	for name := range e.Extensions() {
+		if vErr := validateStrictExtensionName(name); vErr != nil {
+			errs[name] = vErr
+		}
+	}
+
+	if _, ok := errs["source"]; !ok {
+		if source := e.Source(); source == "" {
+			errs["source"] = fmt.Errorf("MUST be a non-empty URI-reference")
+		} else if _, pErr := url.Parse(source); pErr != nil {
+			errs["source"] = fmt.Errorf("MUST be a non-empty URI-reference: %w", pErr)
+		}
+	}
+
+	if t := e.Time(); !t.IsZero() {
+		if year := t.UTC().Year(); year < 0 || year > 9999 {
+			errs["time"] = fmt.Errorf("if present, MUST adhere to the format specified in RFC 3339: year %d out of range", year)
+		}
+	}
+
+	// schemaurl of the spec 0.3 is a URI-reference
+	if dataSchema := e.DataSchema(); dataSchema != "" && e.SpecVersion() == CloudEventsVersionV1 {
+		if u, pErr := url.Parse(dataSchema); pErr != nil || !u.IsAbs() {
+			errs["dataschema"] = fmt.Errorf("if present, MUST be an absolute URI")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidationWarnings returns the violations of these recommendations of the spec 1.0.2, which don't make the event
+// invalid:
+//   - the extension names SHOULD NOT exceed StrictMaxExtensionNameLength characters
+func (e Event) ValidationWarnings() error {
+	if e.Context == nil {
+		return nil
+	}
+	warnings := ValidationError{}
+	for name := range e.Extensions() {
+		if len(name) > StrictMaxExtensionNameLength {
+			warnings[name] = fmt.Errorf("CloudEvents attribute names SHOULD NOT exceed %d characters", StrictMaxExtensionNameLength)
+		}
+	}
+	if len(warnings) > 0 {
+		return warnings
+	}
+	return nil
+}
+
+func validateStrictExtensionName(name string) error {
+	if len(name) < 1 {
+		return fmt.Errorf("CloudEvents attribute names MUST NOT be empty")
+	}
+	for _, c := range name {
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')) {
+			return fmt.Errorf("CloudEvents attribute names MUST consist of lower-case letters ('a' to 'z') or digits ('0' to '9') from the ASCII character set")
+		}
+	}
+	return nil
+}
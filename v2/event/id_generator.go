@@ -0,0 +1,166 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator generates the id of an event.
+type IDGenerator interface {
+	// NewID returns a new id for e. e is provided to the generators deriving the id from the event content,
+	// its id is ignored.
+	NewID(e Event) (string, error)
+}
+
+// IDGeneratorFunc is a type alias to implement an IDGenerator through a function pointer
+type IDGeneratorFunc func(e Event) (string, error)
+
+// NewID implements IDGenerator.NewID
+func (f IDGeneratorFunc) NewID(e Event) (string, error) {
+	return f(e)
+}
+
+var (
+	// UUIDv4IDGenerator generates random UUIDs (version 4).
+	UUIDv4IDGenerator IDGenerator = IDGeneratorFunc(func(Event) (string, error) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	})
+
+	// UUIDv7IDGenerator generates time-ordered UUIDs (version 7): the ids sort by their generation time,
+	// improving the locality when they're used as storage keys. The ids generated in the same millisecond
+	// are monotonic.
+	UUIDv7IDGenerator IDGenerator = IDGeneratorFunc(func(Event) (string, error) {
+		id, err := uuidv7IDs.next()
+		if err != nil {
+			return "", err
+		}
+		return uuid.UUID(id).String(), nil
+	})
+
+	// ULIDIDGenerator generates ULIDs (https://github.com/ulid/spec): 26 characters, lexicographically
+	// sortable by their generation time. The ids generated in the same millisecond are monotonic.
+	ULIDIDGenerator IDGenerator = IDGeneratorFunc(func(Event) (string, error) {
+		id, err := ulidIDs.next()
+		if err != nil {
+			return "", err
+		}
+		return encodeCrockfordBase32(id), nil
+	})
+)
+
+var (
+	uuidv7IDs = &timeOrderedIDs{
+		random: [16]byte{6: 0x0f, 7: 0xff, 8: 0x3f, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff},
+		fixed: [16]byte{
+			6: 0x70, // Version 7
+			8: 0x80, // Variant RFC 4122
+		},
+	}
+	ulidIDs = &timeOrderedIDs{
+		random: [16]byte{6: 0xff, 7: 0xff, 8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff},
+	}
+)
+
+// NewContentIDGenerator returns an IDGenerator deriving the id from the source, the type and the data of the event,
+// as a name-based UUID (version 5) in the provided namespace. The same logical event always gets the same id,
+// so the retries of a send can be deduplicated by the receivers.
+func NewContentIDGenerator(namespace uuid.UUID) IDGenerator {
+	return IDGeneratorFunc(func(e Event) (string, error) {
+		dataHash := sha256.Sum256(e.Data())
+		name := make([]byte, 0, len(e.Source())+len(e.Type())+2+len(dataHash))
+		name = append(name, e.Source()...)
+		name = append(name, 0)
+		name = append(name, e.Type()...)
+		name = append(name, 0)
+		name = append(name, dataHash[:]...)
+		return uuid.NewSHA1(namespace, name).String(), nil
+	})
+}
+
+// timeOrderedIDs generates 16 bytes ids made of a 48 bit unix timestamp in milliseconds followed by random bits.
+// Within the same millisecond, the random bits of the previous id are incremented, so the ids are monotonic.
+type timeOrderedIDs struct {
+	// random holds the random bits of each byte, the low bits, and fixed the value of the other bits
+	random [16]byte
+	fixed  [16]byte
+
+	mu   sync.Mutex
+	last [16]byte
+}
+
+func (g *timeOrderedIDs) next() ([16]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var ts [8]byte
+	copy(ts[2:], g.last[:6])
+	last := binary.BigEndian.Uint64(ts[:])
+	now := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+
+	id := g.last
+	if now <= last && g.increment(&id) {
+		g.last = id
+		return id, nil
+	}
+	if now <= last {
+		// The random bits overflowed, or the clock went backwards: move to the next millisecond
+		now = last + 1
+	}
+
+	binary.BigEndian.PutUint64(ts[:], now)
+	copy(id[:6], ts[2:])
+	if _, err := io.ReadFull(rand.Reader, id[6:]); err != nil {
+		return id, err
+	}
+	for i := 6; i < len(id); i++ {
+		id[i] = id[i]&g.random[i] | g.fixed[i]
+	}
+	g.last = id
+	return id, nil
+}
+
+// increment increments the random bits of id, returning false if they overflow
+func (g *timeOrderedIDs) increment(id *[16]byte) bool {
+	for i := len(id) - 1; i >= 6; i-- {
+		mask := g.random[i]
+		if v := id[i] & mask; v != mask {
+			id[i] = id[i]&^mask | (v + 1)
+			return true
+		}
+		id[i] &^= mask
+	}
+	return false
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// encodeCrockfordBase32 encodes the 128 bits of id in 26 characters, padding them with 2 leading zero bits
+func encodeCrockfordBase32(id [16]byte) string {
+	var out [26]byte
+	for i := range out {
+		var v byte
+		for b := 0; b < 5; b++ {
+			v <<= 1
+			if bit := i*5 + b - 2; bit >= 0 && id[bit/8]&(0x80>>uint(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+		out[i] = crockfordAlphabet[v]
+	}
+	return string(out[:])
+}
@@ -0,0 +1,116 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package event_test
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestIDGenerators(t *testing.T) {
+	tests := map[string]struct {
+		generator event.IDGenerator
+		pattern   string
+	}{
+		"uuidv4": {generator: event.UUIDv4IDGenerator, pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		"uuidv7": {generator: event.UUIDv7IDGenerator, pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		"ulid":   {generator: event.ULIDIDGenerator, pattern: `^[0-7][0-9A-HJKMNP-TV-Z]{25}$`},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			seen := map[string]bool{}
+			for i := 0; i < 100; i++ {
+				id, err := tc.generator.NewID(event.New())
+				require.NoError(t, err)
+				require.Regexp(t, regexp.MustCompile(tc.pattern), id)
+				require.False(t, seen[id], "duplicated id %s", id)
+				seen[id] = true
+			}
+		})
+	}
+}
+
+func TestIDGenerators_timeOrdered(t *testing.T) {
+	for n, g := range map[string]event.IDGenerator{"uuidv7": event.UUIDv7IDGenerator, "ulid": event.ULIDIDGenerator} {
+		t.Run(n, func(t *testing.T) {
+			var ids []string
+			for i := 0; i < 5; i++ {
+				id, err := g.NewID(event.New())
+				require.NoError(t, err)
+				ids = append(ids, id)
+				time.Sleep(2 * time.Millisecond)
+			}
+			require.True(t, sort.StringsAreSorted(ids), "ids are not sorted: %v", ids)
+		})
+	}
+}
+
+func TestIDGenerators_monotonic(t *testing.T) {
+	for n, g := range map[string]event.IDGenerator{"uuidv7": event.UUIDv7IDGenerator, "ulid": event.ULIDIDGenerator} {
+		t.Run(n, func(t *testing.T) {
+			// Most of the ids are generated in the same millisecond
+			ids := make([]string, 1000)
+			for i := range ids {
+				id, err := g.NewID(event.New())
+				require.NoError(t, err)
+				ids[i] = id
+			}
+			require.True(t, sort.SliceIsSorted(ids, func(i, j int) bool { return ids[i] <= ids[j] }))
+			for i := 1; i < len(ids); i++ {
+				require.NotEqual(t, ids[i-1], ids[i])
+			}
+			if n == "uuidv7" {
+				for _, id := range ids {
+					u := uuid.MustParse(id)
+					require.Equal(t, uuid.Version(7), u.Version())
+					require.Equal(t, uuid.RFC4122, u.Variant())
+				}
+			}
+		})
+	}
+}
+
+func TestContentIDGenerator(t *testing.T) {
+	g := event.NewContentIDGenerator(uuid.NameSpaceURL)
+
+	newEvent := func(source, eventType, data string) event.Event {
+		e := event.New()
+		e.SetID("ignored")
+		e.SetSource(source)
+		e.SetType(eventType)
+		require.NoError(t, e.SetData(event.TextPlain, data))
+		return e
+	}
+	id := func(e event.Event) string {
+		id, err := g.NewID(e)
+		require.NoError(t, err)
+		return id
+	}
+
+	want := id(newEvent("/source", "type", "data"))
+	require.Equal(t, uuid.Version(5), uuid.MustParse(want).Version())
+
+	e := newEvent("/source", "type", "data")
+	e.SetID("other")
+	e.SetExtension("exta", "a")
+	require.Equal(t, want, id(e))
+
+	require.NotEqual(t, want, id(newEvent("/other", "type", "data")))
+	require.NotEqual(t, want, id(newEvent("/source", "other", "data")))
+	require.NotEqual(t, want, id(newEvent("/source", "type", "other")))
+	require.NotEqual(t, want, id(newEvent("/sourcetype", "", "data")))
+
+	other, err := event.NewContentIDGenerator(uuid.NameSpaceOID).NewID(newEvent("/source", "type", "data"))
+	require.NoError(t, err)
+	require.NotEqual(t, want, other)
+}
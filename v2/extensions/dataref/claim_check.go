@@ -0,0 +1,83 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// ClaimCheck offloads the data of events bigger than a threshold to a BlobStore,
+// replacing it with the dataref extension, and retrieves it back on receive.
+type ClaimCheck struct {
+	store     BlobStore
+	threshold int
+}
+
+// NewClaimCheck returns a ClaimCheck that offloads data longer than threshold bytes to store.
+func NewClaimCheck(store BlobStore, threshold int) *ClaimCheck {
+	return &ClaimCheck{store: store, threshold: threshold}
+}
+
+// Offload moves the data of e to the store if it exceeds the threshold,
+// setting the dataref extension. It implements client.OutboundEventInterceptor.
+func (c *ClaimCheck) Offload(ctx context.Context, e *event.Event) error {
+	data := e.Data()
+	if len(data) <= c.threshold {
+		return nil
+	}
+
+	ref, err := c.store.Put(ctx, *e, data)
+	if err != nil {
+		return fmt.Errorf("failed to offload event data: %w", err)
+	}
+
+	e.Context = e.Context.Clone()
+	if err := extensions.AddDataRefExtension(e, ref); err != nil {
+		return err
+	}
+	e.DataEncoded = nil
+	e.DataBase64 = false
+	return nil
+}
+
+// Retrieve fetches the data of e from the store if the event has the dataref extension
+// and no data, and removes the extension. It implements client.InboundEventInterceptor: if the data cannot be
+// retrieved, the message is NACKed.
+func (c *ClaimCheck) Retrieve(ctx context.Context, e *event.Event) (context.Context, error) {
+	dr, ok := extensions.GetDataRefExtension(*e)
+	if !ok || e.Data() != nil {
+		return ctx, nil
+	}
+
+	data, err := c.store.Get(ctx, dr.DataRef)
+	if err != nil {
+		return ctx, protocol.NewReceipt(false, "failed to retrieve event data from %q: %w", dr.DataRef, err)
+	}
+	e.DataEncoded = data
+	// The data is inlined, so the event forwarded downstream must not be fetched again
+	if err := e.Context.SetExtension(extensions.DataRefExtensionKey, nil); err != nil {
+		return ctx, err
+	}
+	return ctx, nil
+}
+
+// WithClaimCheck configures the client to offload the data of sent events longer than
+// threshold bytes to store, and to retrieve the data of received events before invoking the receiver.
+func WithClaimCheck(store BlobStore, threshold int) client.Option {
+	c := NewClaimCheck(store, threshold)
+	return func(i interface{}) error {
+		if err := client.WithOutboundEventInterceptor(c.Offload)(i); err != nil {
+			return err
+		}
+		return client.WithInboundEventInterceptor(c.Retrieve)(i)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienttest "github.com/cloudevents/sdk-go/v2/client/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/dataref"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func largeEvent(t *testing.T, size int) event.Event {
+	e := test.MinEvent()
+	require.NoError(t, e.SetData(event.TextPlain, strings.Repeat("a", size)))
+	return e
+}
+
+func TestClaimCheck_Offload(t *testing.T) {
+	store := dataref.NewMemoryStore()
+	cc := dataref.NewClaimCheck(store, 10)
+
+	small := largeEvent(t, 10)
+	require.NoError(t, cc.Offload(context.TODO(), &small))
+	require.Len(t, small.Data(), 10)
+	_, ok := extensions.GetDataRefExtension(small)
+	require.False(t, ok)
+
+	large := largeEvent(t, 11)
+	original := large.Clone()
+	require.NoError(t, cc.Offload(context.TODO(), &large))
+	require.Nil(t, large.Data())
+	require.Equal(t, event.TextPlain, large.DataContentType())
+	require.Equal(t, 1, store.Len())
+	require.Empty(t, original.Extensions(), "offload must not modify the original event context")
+
+	dr, ok := extensions.GetDataRefExtension(large)
+	require.True(t, ok)
+	data, err := store.Get(context.TODO(), dr.DataRef)
+	require.NoError(t, err)
+	require.Equal(t, original.Data(), data)
+
+	_, err = cc.Retrieve(context.TODO(), &large)
+	require.NoError(t, err)
+	require.Equal(t, original.Data(), large.Data())
+	_, ok = extensions.GetDataRefExtension(large)
+	require.False(t, ok, "the dataref extension must be removed once the data is inlined")
+}
+
+func TestClaimCheck_RetrieveNotFound(t *testing.T) {
+	cc := dataref.NewClaimCheck(dataref.NewMemoryStore(), 10)
+
+	e := test.MinEvent()
+	require.NoError(t, extensions.AddDataRefExtension(&e, "memory:unknown"))
+	_, err := cc.Retrieve(context.TODO(), &e)
+	require.True(t, protocol.IsNACK(err))
+	require.True(t, protocol.ResultIs(err, dataref.ErrNotFound))
+}
+
+func TestWithClaimCheck(t *testing.T) {
+	store := dataref.NewMemoryStore()
+	in := largeEvent(t, 1024)
+
+	clienttest.SendReceive(t, func() interface{} {
+		return gochan.New()
+	}, in, func(out event.Event) {
+		// The data was offloaded, then inlined again
+		_, ok := extensions.GetDataRefExtension(out)
+		require.False(t, ok)
+		require.Equal(t, in.Data(), out.Data())
+	}, dataref.WithClaimCheck(store, 512))
+	require.Equal(t, 1, store.Len())
+}
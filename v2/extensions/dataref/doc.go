@@ -0,0 +1,8 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package dataref implements the claim check pattern using the dataref extension:
+// large event data is offloaded to a BlobStore on send and retrieved back on receive.
+package dataref
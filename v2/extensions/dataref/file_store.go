@@ -0,0 +1,69 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// FileStore is a BlobStore that writes the data in files of a local directory.
+// References are file:// URIs pointing to the files.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore writing in the directory dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: abs}, nil
+}
+
+func (s *FileStore) Put(_ context.Context, _ event.Event, data []byte) (string, error) {
+	path := filepath.Join(s.dir, uuid.New().String())
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+func (s *FileStore) Get(_ context.Context, ref string) ([]byte, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported dataref scheme %q", u.Scheme)
+	}
+
+	// Never read files outside the store directory
+	path := filepath.Clean(filepath.FromSlash(u.Path))
+	if filepath.Dir(path) != s.dir {
+		return nil, fmt.Errorf("dataref %q is outside the store directory", ref)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+var _ BlobStore = (*FileStore)(nil)
@@ -0,0 +1,47 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions/dataref"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dataref")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := dataref.NewFileStore(filepath.Join(dir, "blobs"))
+	require.NoError(t, err)
+
+	ref, err := store.Put(context.TODO(), test.MinEvent(), []byte("hello world"))
+	require.NoError(t, err)
+	require.Contains(t, ref, "file://")
+
+	data, err := store.Get(context.TODO(), ref)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello world"), data)
+
+	_, err = store.Get(context.TODO(), ref+"-missing")
+	require.Equal(t, dataref.ErrNotFound, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0600))
+	_, err = store.Get(context.TODO(), "file://"+filepath.ToSlash(filepath.Join(dir, "blobs", "..", "secret")))
+	require.Error(t, err)
+	require.NotEqual(t, dataref.ErrNotFound, err)
+
+	_, err = store.Get(context.TODO(), "http://example.com/blob")
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+const memoryScheme = "memory:"
+
+// MemoryStore is a BlobStore that keeps the data in memory, useful for tests.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: make(map[string][]byte)}
+}
+
+func (s *MemoryStore) Put(_ context.Context, _ event.Event, data []byte) (string, error) {
+	ref := memoryScheme + uuid.New().String()
+	buf := make([]byte, len(data))
+	copy(buf, data)
+
+	s.mu.Lock()
+	s.blobs[ref] = buf
+	s.mu.Unlock()
+	return ref, nil
+}
+
+func (s *MemoryStore) Get(_ context.Context, ref string) ([]byte, error) {
+	s.mu.RLock()
+	data, ok := s.blobs[ref]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// Len returns the number of stored blobs
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.blobs)
+}
+
+var _ BlobStore = (*MemoryStore)(nil)
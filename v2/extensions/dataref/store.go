@@ -0,0 +1,26 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package dataref
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// ErrNotFound is returned by a BlobStore when the data for a reference doesn't exist.
+var ErrNotFound = errors.New("dataref: data not found")
+
+// BlobStore stores offloaded event data.
+type BlobStore interface {
+	// Put stores the data of the event e and returns the reference to retrieve it,
+	// which is used as value of the dataref extension.
+	Put(ctx context.Context, e event.Event, data []byte) (string, error)
+
+	// Get returns the data stored with the provided reference.
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
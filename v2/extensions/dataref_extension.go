@@ -0,0 +1,40 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions
+
+import (
+	"net/url"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const DataRefExtensionKey = "dataref"
+
+// DataRefExtension represents the dataref extension (claim check pattern) for cloudevents context.
+// See https://github.com/cloudevents/spec/blob/v1.0.1/extensions/dataref.md
+type DataRefExtension struct {
+	DataRef string `json:"dataref"`
+}
+
+// AddDataRefExtension adds the dataref attribute to the cloudevents context
+func AddDataRefExtension(e *event.Event, dataRef string) error {
+	if _, err := url.Parse(dataRef); err != nil {
+		return err
+	}
+	e.SetExtension(DataRefExtensionKey, dataRef)
+	return nil
+}
+
+// GetDataRefExtension returns the dataref extension of the event, if any
+func GetDataRefExtension(e event.Event) (DataRefExtension, bool) {
+	if dr, ok := e.Extensions()[DataRefExtensionKey]; ok {
+		if drStr, err := types.ToString(dr); err == nil {
+			return DataRefExtension{DataRef: drStr}, true
+		}
+	}
+	return DataRefExtension{}, false
+}
@@ -0,0 +1,29 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestDataRefExtension(t *testing.T) {
+	e := test.MinEvent()
+
+	_, ok := extensions.GetDataRefExtension(e)
+	require.False(t, ok)
+
+	require.Error(t, extensions.AddDataRefExtension(&e, "://not a uri"))
+	require.NoError(t, extensions.AddDataRefExtension(&e, "https://example.com/blobs/42"))
+
+	got, ok := extensions.GetDataRefExtension(e)
+	require.True(t, ok)
+	require.Equal(t, "https://example.com/blobs/42", got.DataRef)
+}
@@ -0,0 +1,9 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package encryption implements payload encryption of events: an Encryptor replaces the data
+// with a JWE envelope, leaving the attributes in clear text so routing and filtering keep working,
+// and a Decryptor restores the original data on receive.
+package encryption
@@ -0,0 +1,153 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// EncryptedContentType is the datacontenttype of events with encrypted data:
+	// the data is the compact serialization of a JWE.
+	EncryptedContentType = "application/jose"
+	// OriginalContentTypeExtensionKey is the extension holding the datacontenttype of the encrypted data.
+	OriginalContentTypeExtensionKey = "encryptedcontenttype"
+	// KeyIDExtensionKey is the extension holding the id of the key used to encrypt the data.
+	KeyIDExtensionKey = "encryptionkeyid"
+)
+
+// Encryptor encrypts the data of events with the keys of a KeyResolver.
+type Encryptor struct {
+	keys KeyResolver
+}
+
+// NewEncryptor returns an Encryptor using the encryption keys of keys.
+func NewEncryptor(keys KeyResolver) *Encryptor {
+	return &Encryptor{keys: keys}
+}
+
+// Encrypt replaces the data of e with a JWE and its datacontenttype with EncryptedContentType.
+// The original datacontenttype and the key id are stored in extensions.
+// Events without data are left untouched.
+// It implements client.OutboundEventInterceptor.
+func (en *Encryptor) Encrypt(ctx context.Context, e *event.Event) error {
+	if e.Data() == nil {
+		return nil
+	}
+	key, err := en.keys.EncryptionKey(ctx, *e)
+	if err != nil {
+		return fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	contentType := e.DataContentType()
+	jwe, err := encrypt(header{ContentType: contentType, Base64: e.DataBase64}, key, e.Data())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt event data: %w", err)
+	}
+
+	ec := e.Context.Clone()
+	if err := ec.SetDataContentType(EncryptedContentType); err != nil {
+		return err
+	}
+	if contentType != "" {
+		if err := ec.SetExtension(OriginalContentTypeExtensionKey, contentType); err != nil {
+			return err
+		}
+	}
+	if err := ec.SetExtension(KeyIDExtensionKey, key.ID); err != nil {
+		return err
+	}
+	e.Context = ec
+	e.DataEncoded = []byte(jwe)
+	e.DataBase64 = false
+	return nil
+}
+
+// Decryptor decrypts the data of events with the keys of a KeyResolver.
+type Decryptor struct {
+	keys KeyResolver
+}
+
+// NewDecryptor returns a Decryptor using the decryption keys of keys.
+func NewDecryptor(keys KeyResolver) *Decryptor {
+	return &Decryptor{keys: keys}
+}
+
+// DecryptEvent restores the original data and datacontenttype of e, removing the encryption extensions.
+// Events not encrypted by an Encryptor are left untouched.
+// The key id and the original datacontenttype are read from the JWE protected header, which is
+// authenticated, rather than from the extensions.
+func (d *Decryptor) DecryptEvent(ctx context.Context, e *event.Event) error {
+	if !IsEncrypted(*e) {
+		return nil
+	}
+	h, parts, err := parse(string(e.Data()))
+	if err != nil {
+		return err
+	}
+	key, err := d.keys.DecryptionKey(ctx, h.KeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get decryption key: %w", err)
+	}
+	plaintext, err := decrypt(h, parts, key)
+	if err != nil {
+		return err
+	}
+
+	ec := e.Context.Clone()
+	if err := ec.SetDataContentType(h.ContentType); err != nil {
+		return err
+	}
+	if err := ec.SetExtension(OriginalContentTypeExtensionKey, nil); err != nil {
+		return err
+	}
+	if err := ec.SetExtension(KeyIDExtensionKey, nil); err != nil {
+		return err
+	}
+	e.Context = ec
+	e.DataEncoded = plaintext
+	e.DataBase64 = h.Base64
+	return nil
+}
+
+// Decrypt restores the original data of e, rejecting it as malformed if the decryption fails.
+// It implements client.InboundEventInterceptor.
+func (d *Decryptor) Decrypt(ctx context.Context, e *event.Event) (context.Context, error) {
+	if err := d.DecryptEvent(ctx, e); err != nil {
+		return ctx, client.NewMalformedEventResult(fmt.Errorf("decryption failed: %w", err))
+	}
+	return ctx, nil
+}
+
+// IsEncrypted returns true if the data of e has been encrypted by an Encryptor.
+func IsEncrypted(e event.Event) bool {
+	if e.DataContentType() != EncryptedContentType {
+		return false
+	}
+	kid, ok := e.Extensions()[KeyIDExtensionKey]
+	if !ok {
+		return false
+	}
+	_, err := types.ToString(kid)
+	return err == nil
+}
+
+// WithEncryption adds an outbound event interceptor encrypting the data of the events to the client.
+// To sign encrypted events, add signature.WithSigning after this option.
+func WithEncryption(keys KeyResolver) client.Option {
+	return client.WithOutboundEventInterceptor(NewEncryptor(keys).Encrypt)
+}
+
+// WithDecryption adds an inbound event interceptor decrypting the data of the events to the client,
+// before the receiver function reads it with DataAs.
+func WithDecryption(keys KeyResolver) client.Option {
+	return client.WithInboundEventInterceptor(NewDecryptor(keys).Decrypt)
+}
@@ -0,0 +1,143 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	clienttest "github.com/cloudevents/sdk-go/v2/client/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions/encryption"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func newKey(t *testing.T, id string, size int) encryption.Key {
+	k := make([]byte, size)
+	_, err := rand.Read(k)
+	require.NoError(t, err)
+	return encryption.Key{ID: id, Key: k}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		keys, err := encryption.NewKeyRing(newKey(t, "key", size))
+		require.NoError(t, err)
+		encryptor := encryption.NewEncryptor(keys)
+		decryptor := encryption.NewDecryptor(keys)
+
+		test.EachEvent(t, test.Events(), func(t *testing.T, e event.Event) {
+			e = test.ConvertEventExtensionsToString(t, e.Clone())
+			encrypted := e.Clone()
+			require.NoError(t, encryptor.Encrypt(context.TODO(), &encrypted))
+
+			if e.Data() == nil {
+				test.AssertEventEquals(t, e, encrypted)
+				return
+			}
+			require.True(t, encryption.IsEncrypted(encrypted))
+			require.Equal(t, encryption.EncryptedContentType, encrypted.DataContentType())
+			require.Equal(t, "key", encrypted.Extensions()[encryption.KeyIDExtensionKey])
+			require.Equal(t, e.DataContentType(), encrypted.Extensions()[encryption.OriginalContentTypeExtensionKey])
+			require.NotContains(t, string(encrypted.Data()), string(e.Data()))
+			// Attributes stay in clear text
+			require.Equal(t, e.Type(), encrypted.Type())
+			require.Equal(t, e.Source(), encrypted.Source())
+
+			// The encrypted event survives the conversion to binary and structured messages
+			for _, m := range []binding.Message{bindingtest.MustCreateMockBinaryMessage(encrypted), bindingtest.MustCreateMockStructuredMessage(t, encrypted)} {
+				got, err := binding.ToEvent(context.TODO(), m)
+				require.NoError(t, err)
+				require.NoError(t, decryptor.DecryptEvent(context.TODO(), got))
+				test.AssertEventEquals(t, e, *got)
+			}
+		})
+	}
+}
+
+func TestDecrypt_NotEncrypted(t *testing.T) {
+	keys, err := encryption.NewKeyRing(newKey(t, "key", 32))
+	require.NoError(t, err)
+
+	e := test.FullEvent()
+	want := e.Clone()
+	_, err = encryption.NewDecryptor(keys).Decrypt(context.TODO(), &e)
+	require.NoError(t, err)
+	test.AssertEventEquals(t, want, e)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	keys, err := encryption.NewKeyRing(newKey(t, "key", 32))
+	require.NoError(t, err)
+
+	e := test.FullEvent()
+	require.NoError(t, encryption.NewEncryptor(keys).Encrypt(context.TODO(), &e))
+	// Alter the first character of the iv
+	i := bytes.Index(e.DataEncoded, []byte("..")) + 2
+	if e.DataEncoded[i] == 'A' {
+		e.DataEncoded[i] = 'B'
+	} else {
+		e.DataEncoded[i] = 'A'
+	}
+
+	_, err = encryption.NewDecryptor(keys).Decrypt(context.TODO(), &e)
+	require.True(t, protocol.IsNACK(err))
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	oldKey, newKey := newKey(t, "old", 32), newKey(t, "new", 16)
+	keys, err := encryption.NewKeyRing(oldKey)
+	require.NoError(t, err)
+	encryptor := encryption.NewEncryptor(keys)
+	decryptor := encryption.NewDecryptor(keys)
+
+	encryptedWithOld := test.FullEvent()
+	require.NoError(t, encryptor.Encrypt(context.TODO(), &encryptedWithOld))
+
+	require.NoError(t, keys.Rotate(newKey))
+	encryptedWithNew := test.FullEvent()
+	require.NoError(t, encryptor.Encrypt(context.TODO(), &encryptedWithNew))
+	require.Equal(t, "new", encryptedWithNew.Extensions()[encryption.KeyIDExtensionKey])
+
+	// Events encrypted with the previous key can still be decrypted
+	e := encryptedWithOld.Clone()
+	require.NoError(t, decryptor.DecryptEvent(context.TODO(), &e))
+	e = encryptedWithNew.Clone()
+	require.NoError(t, decryptor.DecryptEvent(context.TODO(), &e))
+
+	require.Error(t, keys.Retire("new"))
+	require.NoError(t, keys.Retire("old"))
+	e = encryptedWithOld.Clone()
+	require.Error(t, decryptor.DecryptEvent(context.TODO(), &e))
+}
+
+func TestNewKeyRing_InvalidKey(t *testing.T) {
+	_, err := encryption.NewKeyRing(encryption.Key{ID: "key", Key: []byte("too short")})
+	require.Error(t, err)
+	_, err = encryption.NewKeyRing(encryption.Key{Key: make([]byte, 32)})
+	require.Error(t, err)
+}
+
+func TestWithEncryptionAndDecryption(t *testing.T) {
+	keys, err := encryption.NewKeyRing(newKey(t, "key", 32))
+	require.NoError(t, err)
+
+	in := test.ConvertEventExtensionsToString(t, test.FullEvent())
+	clienttest.SendReceive(t, func() interface{} {
+		return gochan.New()
+	}, in, func(out event.Event) {
+		require.False(t, encryption.IsEncrypted(out))
+		test.AssertEventEquals(t, in, test.ConvertEventExtensionsToString(t, out))
+	}, encryption.WithEncryption(keys), encryption.WithDecryption(keys))
+}
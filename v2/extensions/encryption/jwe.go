@@ -0,0 +1,136 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// directAlgorithm is the JWE key management algorithm using the shared key as content encryption key
+const directAlgorithm = "dir"
+
+// header is the JWE protected header
+type header struct {
+	Algorithm  string `json:"alg"`
+	Encryption string `json:"enc"`
+	KeyID      string `json:"kid"`
+	// ContentType is the original datacontenttype
+	ContentType string `json:"cty,omitempty"`
+	// Base64 reports whether the original data was binary, i.e. carried in data_base64
+	Base64 bool `json:"ceb64,omitempty"`
+}
+
+func contentEncryptionOf(key Key) (string, error) {
+	switch len(key.Key) {
+	case 16:
+		return "A128GCM", nil
+	case 24:
+		return "A192GCM", nil
+	case 32:
+		return "A256GCM", nil
+	}
+	return "", fmt.Errorf("key %q must be 16, 24 or 32 bytes long, got %d", key.ID, len(key.Key))
+}
+
+// encrypt returns the compact serialization of a JWE of plaintext: header..iv.ciphertext.tag
+func encrypt(h header, key Key, plaintext []byte) (string, error) {
+	enc, err := contentEncryptionOf(key)
+	if err != nil {
+		return "", err
+	}
+	h.Algorithm = directAlgorithm
+	h.Encryption = enc
+	h.KeyID = key.ID
+
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(hb)
+
+	aead, err := newGCM(key.Key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nil, iv, plaintext, []byte(encodedHeader))
+	ciphertext, tag := sealed[:len(sealed)-aead.Overhead()], sealed[len(sealed)-aead.Overhead():]
+
+	return strings.Join([]string{
+		encodedHeader,
+		"",
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// parse parses the compact serialization of a JWE, returning its protected header
+func parse(jwe string) (h header, parts []string, err error) {
+	parts = strings.Split(jwe, ".")
+	if len(parts) != 5 {
+		return h, nil, fmt.Errorf("malformed JWE")
+	}
+	hb, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return h, nil, fmt.Errorf("malformed JWE header: %w", err)
+	}
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return h, nil, fmt.Errorf("malformed JWE header: %w", err)
+	}
+	if h.Algorithm != directAlgorithm || parts[1] != "" {
+		return h, nil, fmt.Errorf("unsupported JWE algorithm %q", h.Algorithm)
+	}
+	return h, parts, nil
+}
+
+// decrypt decrypts the parts of a JWE returned by parse with key
+func decrypt(h header, parts []string, key Key) ([]byte, error) {
+	enc, err := contentEncryptionOf(key)
+	if err != nil {
+		return nil, err
+	}
+	if enc != h.Encryption {
+		return nil, fmt.Errorf("key %q cannot be used with %s", key.ID, h.Encryption)
+	}
+	var decoded [3][]byte
+	for i, p := range parts[2:] {
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(p); err != nil {
+			return nil, fmt.Errorf("malformed JWE: %w", err)
+		}
+	}
+	iv, ciphertext, tag := decoded[0], decoded[1], decoded[2]
+
+	aead, err := newGCM(key.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() {
+		return nil, fmt.Errorf("malformed JWE: invalid iv length %d", len(iv))
+	}
+	plaintext, err := aead.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt JWE: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
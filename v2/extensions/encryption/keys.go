@@ -0,0 +1,106 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Key is an AES key used for direct encryption of the data.
+// Key.Key must be 16, 24 or 32 bytes long, selecting A128GCM, A192GCM or A256GCM.
+type Key struct {
+	ID  string
+	Key []byte
+}
+
+func (k Key) validate() error {
+	if k.ID == "" {
+		return fmt.Errorf("key id must not be empty")
+	}
+	if _, err := contentEncryptionOf(k); err != nil {
+		return err
+	}
+	return nil
+}
+
+// KeyResolver resolves the keys to encrypt and decrypt events.
+type KeyResolver interface {
+	// EncryptionKey returns the key to encrypt the data of the provided event.
+	EncryptionKey(ctx context.Context, e event.Event) (Key, error)
+	// DecryptionKey returns the key with the provided id.
+	DecryptionKey(ctx context.Context, id string) (Key, error)
+}
+
+// KeyRing is a KeyResolver encrypting with a single active key and decrypting
+// with any of its keys, so that keys can be rotated while events encrypted with
+// previous keys are still in flight.
+// KeyRing is safe for concurrent use.
+type KeyRing struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]Key
+}
+
+// NewKeyRing returns a KeyRing encrypting with active and decrypting with active and keys.
+func NewKeyRing(active Key, keys ...Key) (*KeyRing, error) {
+	kr := &KeyRing{keys: make(map[string]Key)}
+	for _, k := range keys {
+		if err := k.validate(); err != nil {
+			return nil, err
+		}
+		kr.keys[k.ID] = k
+	}
+	if err := kr.Rotate(active); err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// Rotate makes key the active encryption key.
+// The previously active key is kept for decryption until it is retired.
+func (kr *KeyRing) Rotate(key Key) error {
+	if err := key.validate(); err != nil {
+		return err
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.keys[key.ID] = key
+	kr.active = key.ID
+	return nil
+}
+
+// Retire removes the key with the provided id. The active key cannot be retired.
+func (kr *KeyRing) Retire(id string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if id == kr.active {
+		return fmt.Errorf("cannot retire the active key %q", id)
+	}
+	delete(kr.keys, id)
+	return nil
+}
+
+func (kr *KeyRing) EncryptionKey(context.Context, event.Event) (Key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.keys[kr.active], nil
+}
+
+func (kr *KeyRing) DecryptionKey(_ context.Context, id string) (Key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	k, ok := kr.keys[id]
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key id %q", id)
+	}
+	return k, nil
+}
+
+var _ KeyResolver = (*KeyRing)(nil)
@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Policy defines how the Checker handles expired events.
+type Policy int
+
+const (
+	// Drop acknowledges expired events without invoking the receiver.
+	Drop Policy = iota
+	// Nack negatively acknowledges expired events without invoking the receiver.
+	Nack
+	// Sink sends expired events to the configured sink without invoking the receiver.
+	// The received message is acknowledged only if the sink accepts the event.
+	Sink
+)
+
+// SinkFunc receives the expired events when the policy is Sink.
+// client.Client.Send implements it, so a dead letter client can be used as sink.
+type SinkFunc func(ctx context.Context, e event.Event) protocol.Result
+
+// CheckerOption is the function signature of Checker options.
+type CheckerOption func(*Checker)
+
+// WithPolicy sets the policy for expired events. Default is Drop.
+func WithPolicy(policy Policy) CheckerOption {
+	return func(c *Checker) {
+		c.policy = policy
+	}
+}
+
+// WithSink sets the policy for expired events to Sink, sending them to sink.
+func WithSink(sink SinkFunc) CheckerOption {
+	return func(c *Checker) {
+		c.policy = Sink
+		c.sink = sink
+	}
+}
+
+// WithClock sets the function used to get the current time. Default is time.Now.
+func WithClock(now func() time.Time) CheckerOption {
+	return func(c *Checker) {
+		c.now = now
+	}
+}
+
+// Checker checks the expirytime of received events before the receiver is invoked.
+type Checker struct {
+	policy Policy
+	sink   SinkFunc
+	now    func() time.Time
+}
+
+// NewChecker returns a new Checker
+func NewChecker(opts ...CheckerOption) *Checker {
+	c := &Checker{
+		policy: Drop,
+		now:    time.Now,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// Check handles e according to the configured policy if it's expired, otherwise it returns a context
+// requesting the expiry time as deadline of the receiver, see client.WithInvocationDeadline, so the receiver
+// is cancelled when the event becomes stale.
+// It implements client.InboundEventInterceptor. Events without expirytime are ignored.
+func (c *Checker) Check(ctx context.Context, e *event.Event) (context.Context, error) {
+	x, ok := extensions.GetExpiryExtension(*e)
+	if !ok {
+		return ctx, nil
+	}
+
+	if !x.Expired(c.now()) {
+		return client.WithInvocationDeadline(ctx, x.ExpiryTime), nil
+	}
+
+	cecontext.LoggerFrom(ctx).Debugw("received expired event", "id", e.ID(), "expirytime", x.ExpiryTime)
+	switch c.policy {
+	case Nack:
+		return ctx, protocol.NewReceipt(false, "event expired at %s", x.ExpiryTime)
+	case Sink:
+		if c.sink == nil {
+			return ctx, protocol.NewReceipt(false, "event expired at %s, but no sink is configured", x.ExpiryTime)
+		}
+		if res := c.sink(ctx, *e); !protocol.IsACK(res) {
+			return ctx, protocol.NewReceipt(false, "failed to send expired event to the sink: %w", res)
+		}
+	}
+	return ctx, protocol.ResultACK
+}
+
+// WithExpiryCheck adds an inbound event interceptor, backed by a new Checker, to the client.
+func WithExpiryCheck(opts ...CheckerOption) client.Option {
+	return client.WithInboundEventInterceptor(NewChecker(opts...).Check)
+}
@@ -0,0 +1,91 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	clienttest "github.com/cloudevents/sdk-go/v2/client/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/expiry"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+var now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func expiringEvent(expiryTime time.Time) *event.Event {
+	e := test.MinEvent()
+	extensions.ExpiryExtension{ExpiryTime: expiryTime}.AddExpiryAttributes(&e)
+	return &e
+}
+
+func TestChecker_NotExpired(t *testing.T) {
+	checker := expiry.NewChecker(expiry.WithClock(func() time.Time { return now }))
+
+	ctx, err := checker.Check(context.TODO(), expiringEvent(now.Add(time.Hour)))
+	require.NoError(t, err)
+	deadline, ok := client.InvocationDeadlineFrom(ctx)
+	require.True(t, ok)
+	require.True(t, now.Add(time.Hour).Equal(deadline))
+
+	e := test.MinEvent()
+	ctx, err = checker.Check(context.TODO(), &e)
+	require.NoError(t, err)
+	_, ok = client.InvocationDeadlineFrom(ctx)
+	require.False(t, ok)
+}
+
+func TestChecker_Expired(t *testing.T) {
+	var sunk []event.Event
+	sink := func(ctx context.Context, e event.Event) protocol.Result {
+		sunk = append(sunk, e)
+		return nil
+	}
+	failingSink := func(ctx context.Context, e event.Event) protocol.Result {
+		return errors.New("unavailable")
+	}
+
+	tests := []struct {
+		name    string
+		opts    []expiry.CheckerOption
+		wantACK bool
+	}{
+		{name: "drop", wantACK: true},
+		{name: "nack", opts: []expiry.CheckerOption{expiry.WithPolicy(expiry.Nack)}},
+		{name: "sink", opts: []expiry.CheckerOption{expiry.WithSink(sink)}, wantACK: true},
+		{name: "failing sink", opts: []expiry.CheckerOption{expiry.WithSink(failingSink)}},
+		{name: "missing sink", opts: []expiry.CheckerOption{expiry.WithPolicy(expiry.Sink)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := expiry.NewChecker(append(tt.opts, expiry.WithClock(func() time.Time { return now }))...)
+			_, err := checker.Check(context.TODO(), expiringEvent(now))
+			require.Error(t, err)
+			require.Equal(t, tt.wantACK, protocol.IsACK(err))
+			require.Equal(t, !tt.wantACK, protocol.IsNACK(err))
+		})
+	}
+	require.Len(t, sunk, 1)
+}
+
+func TestWithExpiryCheck(t *testing.T) {
+	in := *expiringEvent(time.Now().Add(time.Hour))
+
+	clienttest.SendReceive(t, func() interface{} {
+		return gochan.New()
+	}, in, func(out event.Event) {
+		test.AssertEventEquals(t, in, out)
+	}, expiry.WithExpiryCheck())
+}
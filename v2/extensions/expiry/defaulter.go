@@ -0,0 +1,40 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+)
+
+// NewTTLDefaulter returns a defaulter that will inspect the provided event and set
+// the expirytime extension to the event time (or now, if the time is not set) plus ttl,
+// if the expirytime is not already set.
+func NewTTLDefaulter(ttl time.Duration) client.EventDefaulter {
+	return func(ctx context.Context, e event.Event) event.Event {
+		if e.Context != nil {
+			if _, ok := e.Extensions()[extensions.ExpiryTimeExtensionKey]; !ok {
+				t := e.Time()
+				if t.IsZero() {
+					t = time.Now()
+				}
+				e.Context = e.Context.Clone()
+				extensions.ExpiryExtension{ExpiryTime: t.Add(ttl)}.AddExpiryAttributes(&e)
+			}
+		}
+		return e
+	}
+}
+
+// WithTTL adds a defaulter setting the expirytime of sent events to the end of the defaulter chain.
+// See NewTTLDefaulter.
+func WithTTL(ttl time.Duration) client.Option {
+	return client.WithEventDefaulter(NewTTLDefaulter(ttl))
+}
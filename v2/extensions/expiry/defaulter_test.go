@@ -0,0 +1,44 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/expiry"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestNewTTLDefaulter(t *testing.T) {
+	defaulter := expiry.NewTTLDefaulter(time.Minute)
+
+	in := test.MinEvent()
+	eventTime := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	in.SetTime(eventTime)
+	out := defaulter(context.TODO(), in)
+	require.Empty(t, in.Extensions(), "defaulter must not modify the original event context")
+
+	x, ok := extensions.GetExpiryExtension(out)
+	require.True(t, ok)
+	require.True(t, eventTime.Add(time.Minute).Equal(x.ExpiryTime))
+
+	// Without time, the ttl starts from now
+	out = defaulter(context.TODO(), test.MinEvent())
+	x, ok = extensions.GetExpiryExtension(out)
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Minute), x.ExpiryTime, time.Second)
+
+	// An already set expirytime is kept
+	set := test.MinEvent()
+	extensions.ExpiryExtension{ExpiryTime: eventTime}.AddExpiryAttributes(&set)
+	x, _ = extensions.GetExpiryExtension(defaulter(context.TODO(), set))
+	require.True(t, eventTime.Equal(x.ExpiryTime))
+}
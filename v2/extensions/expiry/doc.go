@@ -0,0 +1,8 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package expiry implements the expirytime extension: a TTL based defaulter sets
+// the expiry of sent events, and a Checker handles stale events on receive.
+package expiry
@@ -0,0 +1,43 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions
+
+import (
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const ExpiryTimeExtensionKey = "expirytime"
+
+// ExpiryExtension represents the expirytime extension for cloudevents context:
+// the timestamp after which the event is stale and should not be processed anymore.
+type ExpiryExtension struct {
+	ExpiryTime time.Time `json:"expirytime"`
+}
+
+// AddExpiryAttributes adds the expirytime attribute to the cloudevents context
+func (x ExpiryExtension) AddExpiryAttributes(e event.EventWriter) {
+	if !x.ExpiryTime.IsZero() {
+		e.SetExtension(ExpiryTimeExtensionKey, types.Timestamp{Time: x.ExpiryTime.UTC()})
+	}
+}
+
+// Expired returns true if the expiry time is set and it's not after now
+func (x ExpiryExtension) Expired(now time.Time) bool {
+	return !x.ExpiryTime.IsZero() && !x.ExpiryTime.After(now)
+}
+
+// GetExpiryExtension returns the expiry extension of the event, if any
+func GetExpiryExtension(e event.Event) (ExpiryExtension, bool) {
+	if v, ok := e.Extensions()[ExpiryTimeExtensionKey]; ok {
+		if t, err := types.ToTime(v); err == nil {
+			return ExpiryExtension{ExpiryTime: t}, true
+		}
+	}
+	return ExpiryExtension{}, false
+}
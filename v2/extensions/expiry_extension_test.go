@@ -0,0 +1,39 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestExpiryExtension(t *testing.T) {
+	e := test.MinEvent()
+	_, ok := extensions.GetExpiryExtension(e)
+	require.False(t, ok)
+
+	expiry := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	extensions.ExpiryExtension{ExpiryTime: expiry}.AddExpiryAttributes(&e)
+
+	got, ok := extensions.GetExpiryExtension(e)
+	require.True(t, ok)
+	require.True(t, expiry.Equal(got.ExpiryTime))
+	require.False(t, got.Expired(expiry.Add(-time.Second)))
+	require.True(t, got.Expired(expiry))
+
+	// Binary protocols carry the extension as string
+	e.SetExtension(extensions.ExpiryTimeExtensionKey, "2021-06-01T12:00:00Z")
+	got, ok = extensions.GetExpiryExtension(e)
+	require.True(t, ok)
+	require.True(t, expiry.Equal(got.ExpiryTime))
+
+	require.False(t, extensions.ExpiryExtension{}.Expired(time.Now()))
+}
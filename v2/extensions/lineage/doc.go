@@ -0,0 +1,9 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lineage tracks the causation and the correlation of events: the events sent while handling
+// a received event, as responses or using the context of the receiver function, get the causationid
+// and correlationid extensions derived from the received event.
+package lineage
@@ -0,0 +1,71 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package lineage
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+)
+
+// Opaque key type used to store the lineage
+type lineageKeyType struct{}
+
+var lineageKey = lineageKeyType{}
+
+// ContextWithCause returns a context carrying the lineage of the events caused by cause.
+func ContextWithCause(ctx context.Context, cause event.Event) context.Context {
+	return context.WithValue(ctx, lineageKey, extensions.DeriveLineageExtension(&cause))
+}
+
+// FromContext returns the lineage of the events sent with ctx, if ctx carries one.
+// Within a receiver function of a client configured WithLineage, it's the lineage derived from the received event.
+func FromContext(ctx context.Context) (extensions.LineageExtension, bool) {
+	x, ok := ctx.Value(lineageKey).(extensions.LineageExtension)
+	return x, ok
+}
+
+// InboundInterceptor is a client.InboundEventInterceptor storing the lineage derived from the received event
+// in the context passed to the receiver function.
+func InboundInterceptor(ctx context.Context, e *event.Event) (context.Context, error) {
+	return ContextWithCause(ctx, *e), nil
+}
+
+// DefaultLineageIfNotSet is a client.EventDefaulter that will inspect the provided event and set
+// its causationid and correlationid from the lineage carried by ctx, if they are not already set.
+// The event which caused the lineage, e.g. a received event sent back as response, is not modified.
+func DefaultLineageIfNotSet(ctx context.Context, e event.Event) event.Event {
+	x, ok := FromContext(ctx)
+	if !ok || e.Context == nil || e.ID() == x.CausationID {
+		return e
+	}
+	current, _ := extensions.GetLineageExtension(&e)
+	if current.CausationID != "" {
+		x.CausationID = ""
+	}
+	if current.CorrelationID != "" {
+		x.CorrelationID = ""
+	}
+	if x.CausationID != "" || x.CorrelationID != "" {
+		e.Context = e.Context.Clone()
+		x.AddLineageAttributes(&e)
+	}
+	return e
+}
+
+// WithLineage adds InboundInterceptor to the inbound interceptor chain and DefaultLineageIfNotSet
+// to the end of the defaulter chain, so the responses and the events sent using the context of
+// the receiver function get the lineage of the received event.
+func WithLineage() client.Option {
+	return func(i interface{}) error {
+		if err := client.WithInboundEventInterceptor(InboundInterceptor)(i); err != nil {
+			return err
+		}
+		return client.WithEventDefaulter(DefaultLineageIfNotSet)(i)
+	}
+}
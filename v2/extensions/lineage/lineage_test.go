@@ -0,0 +1,115 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package lineage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/lineage"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+func newEvent(id string) event.Event {
+	e := event.New()
+	e.SetID(id)
+	e.SetType("type")
+	e.SetSource("/source")
+	return e
+}
+
+func TestWithLineage(t *testing.T) {
+	tests := []struct {
+		name              string
+		cause             event.Event
+		wantCorrelationID string
+	}{
+		{
+			name:              "root event",
+			cause:             newEvent("root"),
+			wantCorrelationID: "root",
+		},
+		{
+			name: "correlated event",
+			cause: func() event.Event {
+				e := newEvent("root")
+				extensions.LineageExtension{CausationID: "parent", CorrelationID: "conversation"}.AddLineageAttributes(&e)
+				return e
+			}(),
+			wantCorrelationID: "conversation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(chan binding.Message)
+			out := make(chan gochan.ChanResponderResponse, 1)
+			sent := make(chan binding.Message, 1)
+
+			sender, err := client.New(gochan.Sender(sent), lineage.WithLineage())
+			require.NoError(t, err)
+			c, err := client.New(&gochan.Responder{In: in, Out: out}, lineage.WithLineage())
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+					x, ok := lineage.FromContext(ctx)
+					require.True(t, ok)
+					require.Equal(t, extensions.LineageExtension{CausationID: "root", CorrelationID: tt.wantCorrelationID}, x)
+
+					require.True(t, protocol.IsACK(sender.Send(ctx, newEvent("followup"))))
+					reply := newEvent("reply")
+					return &reply, nil
+				})
+			}()
+
+			in <- bindingtest.MustCreateMockBinaryMessage(tt.cause)
+
+			for _, m := range []binding.Message{(<-out).Message, <-sent} {
+				got, err := binding.ToEvent(context.TODO(), m)
+				require.NoError(t, err)
+				x, ok := extensions.GetLineageExtension(got)
+				require.True(t, ok)
+				require.Equal(t, extensions.LineageExtension{CausationID: "root", CorrelationID: tt.wantCorrelationID}, x, got.ID())
+			}
+		})
+	}
+}
+
+func TestDefaultLineageIfNotSet(t *testing.T) {
+	ctx := lineage.ContextWithCause(context.Background(), newEvent("root"))
+
+	// Without lineage in the context
+	got := lineage.DefaultLineageIfNotSet(context.Background(), newEvent("child"))
+	_, ok := extensions.GetLineageExtension(&got)
+	require.False(t, ok)
+
+	// The cause itself is not modified
+	cause := newEvent("root")
+	got = lineage.DefaultLineageIfNotSet(ctx, cause)
+	_, ok = extensions.GetLineageExtension(&got)
+	require.False(t, ok)
+
+	// Explicit values are kept
+	e := newEvent("child")
+	extensions.LineageExtension{CorrelationID: "explicit"}.AddLineageAttributes(&e)
+	got = lineage.DefaultLineageIfNotSet(ctx, e)
+	x, _ := extensions.GetLineageExtension(&got)
+	require.Equal(t, extensions.LineageExtension{CausationID: "root", CorrelationID: "explicit"}, x)
+
+	// The original event is not modified
+	x, _ = extensions.GetLineageExtension(&e)
+	require.Equal(t, extensions.LineageExtension{CorrelationID: "explicit"}, x)
+}
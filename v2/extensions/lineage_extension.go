@@ -0,0 +1,56 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions
+
+import (
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	CausationIDExtensionKey   = "causationid"
+	CorrelationIDExtensionKey = "correlationid"
+)
+
+// LineageExtension represents the causationid and correlationid extensions for cloudevents context:
+// the id of the event which caused this event, and the id of the root event of the whole conversation.
+type LineageExtension struct {
+	CausationID   string `json:"causationid"`
+	CorrelationID string `json:"correlationid"`
+}
+
+// AddLineageAttributes adds the causationid and correlationid attributes, when set, to the cloudevents context
+func (x LineageExtension) AddLineageAttributes(e event.EventWriter) {
+	if x.CausationID != "" {
+		e.SetExtension(CausationIDExtensionKey, x.CausationID)
+	}
+	if x.CorrelationID != "" {
+		e.SetExtension(CorrelationIDExtensionKey, x.CorrelationID)
+	}
+}
+
+// GetLineageExtension returns the lineage extension of the event, if at least one of its attributes is set
+func GetLineageExtension(e event.EventReader) (LineageExtension, bool) {
+	var x LineageExtension
+	if v, ok := e.Extensions()[CausationIDExtensionKey]; ok {
+		x.CausationID, _ = types.ToString(v)
+	}
+	if v, ok := e.Extensions()[CorrelationIDExtensionKey]; ok {
+		x.CorrelationID, _ = types.ToString(v)
+	}
+	return x, x.CausationID != "" || x.CorrelationID != ""
+}
+
+// DeriveLineageExtension returns the lineage of an event caused by cause: its causationid is the id of cause,
+// and its correlationid is the one of cause or, if cause has none, the id of cause, as cause is the root event.
+func DeriveLineageExtension(cause event.EventReader) LineageExtension {
+	x, _ := GetLineageExtension(cause)
+	if x.CorrelationID == "" {
+		x.CorrelationID = cause.ID()
+	}
+	x.CausationID = cause.ID()
+	return x
+}
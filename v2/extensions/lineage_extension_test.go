@@ -0,0 +1,31 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestLineageExtension(t *testing.T) {
+	root := test.MinEvent()
+	_, ok := extensions.GetLineageExtension(&root)
+	require.False(t, ok)
+
+	child := test.MinEvent()
+	child.SetID("child")
+	extensions.DeriveLineageExtension(&root).AddLineageAttributes(&child)
+	got, ok := extensions.GetLineageExtension(&child)
+	require.True(t, ok)
+	require.Equal(t, extensions.LineageExtension{CausationID: root.ID(), CorrelationID: root.ID()}, got)
+
+	grandchild := extensions.DeriveLineageExtension(&child)
+	require.Equal(t, extensions.LineageExtension{CausationID: "child", CorrelationID: root.ID()}, grandchild)
+}
@@ -0,0 +1,64 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions
+
+import (
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const PartitionKeyExtension = "partitionkey"
+
+// PartitioningExtension represents the partitioning extension for cloudevents context.
+// Protocol bindings map the partition key to the native partitioning concept of the
+// transport, e.g. the Kafka record key or the Pub/Sub ordering key.
+// See https://github.com/cloudevents/spec/blob/v1.0.1/extensions/partitioning.md
+type PartitioningExtension struct {
+	PartitionKey string `json:"partitionkey"`
+}
+
+// AddPartitioningAttributes adds the partitionkey attribute to the cloudevents context
+func (p PartitioningExtension) AddPartitioningAttributes(e event.EventWriter) {
+	if p.PartitionKey != "" {
+		e.SetExtension(PartitionKeyExtension, p.PartitionKey)
+	}
+}
+
+// GetPartitioningExtension returns the partitioning extension of the event, if any
+func GetPartitioningExtension(event event.Event) (PartitioningExtension, bool) {
+	if pk, ok := event.Extensions()[PartitionKeyExtension]; ok {
+		if pkStr, err := types.ToString(pk); err == nil {
+			return PartitioningExtension{PartitionKey: pkStr}, true
+		}
+	}
+	return PartitioningExtension{}, false
+}
+
+// ReadTransformer returns a transformer that reads the partitionkey attribute into p
+func (p *PartitioningExtension) ReadTransformer() binding.TransformerFunc {
+	return func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		pk := reader.GetExtension(PartitionKeyExtension)
+		if !types.IsZero(pk) {
+			pkFormatted, err := types.Format(pk)
+			if err != nil {
+				return err
+			}
+			p.PartitionKey = pkFormatted
+		}
+		return nil
+	}
+}
+
+// WriteTransformer returns a transformer that writes the partitionkey attribute from p
+func (p *PartitioningExtension) WriteTransformer() binding.TransformerFunc {
+	return func(reader binding.MessageMetadataReader, writer binding.MessageMetadataWriter) error {
+		if p.PartitionKey == "" {
+			return nil
+		}
+		return writer.SetExtension(PartitionKeyExtension, p.PartitionKey)
+	}
+}
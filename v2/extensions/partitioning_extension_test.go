@@ -0,0 +1,94 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestPartitioningExtension_AddAndGet(t *testing.T) {
+	e := test.MinEvent()
+
+	_, ok := extensions.GetPartitioningExtension(e)
+	require.False(t, ok)
+
+	extensions.PartitioningExtension{}.AddPartitioningAttributes(&e)
+	require.Empty(t, e.Extensions())
+
+	extensions.PartitioningExtension{PartitionKey: "customer-42"}.AddPartitioningAttributes(&e)
+	got, ok := extensions.GetPartitioningExtension(e)
+	require.True(t, ok)
+	require.Equal(t, "customer-42", got.PartitionKey)
+}
+
+func TestPartitioningExtension_ReadTransformer(t *testing.T) {
+	e := test.MinEvent()
+	e.Context = e.Context.AsV1()
+	wantExt := extensions.PartitioningExtension{PartitionKey: "customer-42"}
+	wantExt.AddPartitioningAttributes(&e)
+
+	tests := []bindingtest.TransformerTestArgs{
+		{
+			Name:         "Read from Mock Structured message",
+			InputMessage: bindingtest.MustCreateMockStructuredMessage(t, e),
+			WantEvent:    e,
+		},
+		{
+			Name:         "Read from Mock Binary message",
+			InputMessage: bindingtest.MustCreateMockBinaryMessage(e),
+			WantEvent:    e,
+		},
+		{
+			Name:       "Read from Event message",
+			InputEvent: e,
+			WantEvent:  e,
+		},
+	}
+	for _, tt := range tests {
+		haveExt := extensions.PartitioningExtension{}
+		tt.Transformers = binding.Transformers{haveExt.ReadTransformer()}
+		bindingtest.RunTransformerTests(t, context.TODO(), []bindingtest.TransformerTestArgs{tt})
+		require.Equal(t, wantExt.PartitionKey, haveExt.PartitionKey)
+	}
+}
+
+func TestPartitioningExtension_WriteTransformer(t *testing.T) {
+	e := test.MinEvent()
+	e.Context = e.Context.AsV1()
+
+	ext := extensions.PartitioningExtension{PartitionKey: "customer-42"}
+	want := e.Clone()
+	ext.AddPartitioningAttributes(&want)
+
+	bindingtest.RunTransformerTests(t, context.TODO(), []bindingtest.TransformerTestArgs{
+		{
+			Name:         "Write to Mock Structured message",
+			InputMessage: bindingtest.MustCreateMockStructuredMessage(t, e),
+			WantEvent:    want,
+			Transformers: binding.Transformers{ext.WriteTransformer()},
+		},
+		{
+			Name:         "Write to Mock Binary message",
+			InputMessage: bindingtest.MustCreateMockBinaryMessage(e),
+			WantEvent:    want,
+			Transformers: binding.Transformers{ext.WriteTransformer()},
+		},
+		{
+			Name:         "Write to Event message",
+			InputEvent:   e,
+			WantEvent:    want,
+			Transformers: binding.Transformers{ext.WriteTransformer()},
+		},
+	})
+}
@@ -0,0 +1,9 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sequence implements the sequence extension with sequencetype Integer:
+// a Generator assigns increasing sequence values per source on send,
+// a Tracker detects gaps, duplicates and out of order events on receive.
+package sequence
@@ -0,0 +1,52 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequence
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+)
+
+// Generator assigns monotonically increasing Integer sequence values per event source.
+type Generator struct {
+	mu   sync.Mutex
+	last map[string]int32
+}
+
+// NewGenerator returns a Generator starting every source from 1
+func NewGenerator() *Generator {
+	return &Generator{last: make(map[string]int32)}
+}
+
+// Next returns the next sequence value for the provided source
+func (g *Generator) Next(source string) int32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	seq := extensions.NextIntegerSequence(g.last[source])
+	g.last[source] = seq
+	return seq
+}
+
+// DefaultSequenceIfNotSet is a client.EventDefaulter that will inspect the provided event
+// and assign the next sequence value of its source if the sequence extension is not set.
+func (g *Generator) DefaultSequenceIfNotSet(ctx context.Context, e event.Event) event.Event {
+	if e.Context != nil {
+		if _, ok := e.Extensions()[extensions.SequenceExtensionKey]; !ok {
+			e.Context = e.Context.Clone()
+			extensions.NewIntegerSequenceExtension(g.Next(e.Source())).AddSequenceAttributes(&e)
+		}
+	}
+	return e
+}
+
+// WithSequence adds a sequence defaulter, backed by a new Generator, to the end of the defaulter chain.
+func WithSequence() client.Option {
+	return client.WithEventDefaulter(NewGenerator().DefaultSequenceIfNotSet)
+}
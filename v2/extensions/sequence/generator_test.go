@@ -0,0 +1,45 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequence_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/sequence"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestGenerator_DefaultSequenceIfNotSet(t *testing.T) {
+	g := sequence.NewGenerator()
+
+	for i := int32(1); i <= 3; i++ {
+		in := test.MinEvent()
+		out := g.DefaultSequenceIfNotSet(context.TODO(), in)
+		require.Empty(t, in.Extensions(), "defaulter must not modify the original event context")
+
+		ext, ok := extensions.GetSequenceExtension(out)
+		require.True(t, ok)
+		seq, err := ext.Integer()
+		require.NoError(t, err)
+		require.Equal(t, i, seq)
+	}
+
+	// Sources have independent sequences
+	other := test.MinEvent()
+	other.SetSource("/other")
+	ext, _ := extensions.GetSequenceExtension(g.DefaultSequenceIfNotSet(context.TODO(), other))
+	require.Equal(t, "1", ext.Sequence)
+
+	// Already set sequences are kept
+	set := test.MinEvent()
+	extensions.NewIntegerSequenceExtension(100).AddSequenceAttributes(&set)
+	ext, _ = extensions.GetSequenceExtension(g.DefaultSequenceIfNotSet(context.TODO(), set))
+	require.Equal(t, "100", ext.Sequence)
+}
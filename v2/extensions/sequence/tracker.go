@@ -0,0 +1,443 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequence
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// AnomalyKind is the kind of sequence anomaly detected by the Tracker.
+type AnomalyKind int
+
+const (
+	// Gap means one or more sequence values were never received.
+	Gap AnomalyKind = iota
+	// Duplicate means the sequence value was already received.
+	Duplicate
+	// OutOfOrder means the sequence value arrived after it was considered missing.
+	OutOfOrder
+)
+
+func (k AnomalyKind) String() string {
+	switch k {
+	case Gap:
+		return "gap"
+	case Duplicate:
+		return "duplicate"
+	case OutOfOrder:
+		return "out of order"
+	}
+	return "unknown"
+}
+
+// Anomaly describes a sequence anomaly detected by the Tracker.
+type Anomaly struct {
+	Kind   AnomalyKind
+	Source string
+	// Expected is the sequence value the Tracker was expecting.
+	Expected int32
+	// Sequence is the sequence value of the received event.
+	// For gaps, it's the first sequence value received after the gap.
+	Sequence int32
+	// Missing is the number of missing sequence values, set only for gaps.
+	Missing int
+}
+
+// ReportFunc is invoked by the Tracker when an Anomaly is detected.
+type ReportFunc func(ctx context.Context, anomaly Anomaly)
+
+// TrackerOption is the function signature of Tracker options.
+type TrackerOption func(*Tracker)
+
+// WithReportFunc sets the function invoked for every detected anomaly.
+// By default, anomalies are logged as warnings.
+func WithReportFunc(fn ReportFunc) TrackerOption {
+	return func(t *Tracker) {
+		t.report = fn
+	}
+}
+
+// WithDropDuplicates makes the Tracker ACK and drop duplicate events, without invoking the receiver.
+func WithDropDuplicates() TrackerOption {
+	return func(t *Tracker) {
+		t.dropDuplicates = true
+	}
+}
+
+// WithReorderWindow makes the Tracker hold back events that arrive ahead of the expected sequence value,
+// releasing them in order when the missing events arrive: each event of the source is handed to the receiver
+// once the invocation of the previous one completed, see Track.
+// At most size events are held back per source: when the window is full, or when an event is held back longer
+// than timeout, the missing events are reported as a gap and the held back events are released.
+//
+// A held back event blocks the goroutine delivering it, so the window needs the events of a source to be
+// delivered concurrently: with a sequential delivery, like a single poll goroutine or a Kafka partition,
+// the missing event can't arrive before the timeout.
+func WithReorderWindow(size int, timeout time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		t.windowSize = size
+		t.windowTimeout = timeout
+	}
+}
+
+// WithMaxSources sets the number of sources tracked, DefaultMaxSources by default. When a new source exceeds it,
+// the least recently seen source is forgotten: its next event is tracked like the first one.
+func WithMaxSources(max int) TrackerOption {
+	return func(t *Tracker) {
+		if max > 0 {
+			t.maxSources = max
+		}
+	}
+}
+
+// Tracker tracks the last Integer sequence value received per source.
+// The sequence values roll over from 2147483647 to 1, see extensions.NextIntegerSequence: a value is ahead of
+// the expected one when it follows it by less than half of the sequence range, and behind it otherwise.
+type Tracker struct {
+	report         ReportFunc
+	dropDuplicates bool
+	windowSize     int
+	windowTimeout  time.Duration
+	maxSources     int
+
+	mu      sync.Mutex
+	sources map[string]*sourceState
+	// recent holds the sources from the most to the least recently seen
+	recent *list.List
+}
+
+type sourceState struct {
+	source string
+	next   int32
+	// last is closed when the invocation of the last event handed to the receiver completes,
+	// it's nil without reorder window or when the completion can't be observed
+	last    <-chan struct{}
+	waiting map[int32]*heldEvent
+	// skipped holds the ranges of sequence values reported as missing, from the oldest
+	skipped []sequenceRange
+	element *list.Element
+}
+
+// heldEvent is an event held back by the reorder window
+type heldEvent struct {
+	// released is closed when the event is handed to the receiver, once the invocation of previous completes
+	released chan struct{}
+	previous <-chan struct{}
+	// done is closed when the invocation of the event completes
+	done <-chan struct{}
+}
+
+// sequenceRange is the range of length sequence values starting at start
+type sequenceRange struct {
+	start  int32
+	length int64
+}
+
+func (r sequenceRange) contains(seq int32) bool {
+	return distance(r.start, seq) < r.length
+}
+
+// maxSkipped bounds the number of ranges of missing sequence values remembered per source
+const maxSkipped = 1024
+
+// DefaultMaxSources is the default number of sources tracked by a Tracker, see WithMaxSources
+const DefaultMaxSources = 10000
+
+// sequenceSize is the number of Integer sequence values, from 1 to math.MaxInt32
+const sequenceSize = int64(math.MaxInt32)
+
+// distance returns the number of values from a to b, following the sequence and its roll over
+func distance(a, b int32) int64 {
+	return ((int64(b)-int64(a))%sequenceSize + sequenceSize) % sequenceSize
+}
+
+// advance returns the sequence value following seq by n values
+func advance(seq int32, n int64) int32 {
+	v := (int64(seq)-1+n)%sequenceSize + 1
+	if v <= 0 {
+		v += sequenceSize
+	}
+	return int32(v)
+}
+
+// NewTracker returns a new Tracker
+func NewTracker(opts ...TrackerOption) *Tracker {
+	t := &Tracker{
+		report:     logReport,
+		maxSources: DefaultMaxSources,
+		sources:    make(map[string]*sourceState),
+		recent:     list.New(),
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	return t
+}
+
+func logReport(ctx context.Context, a Anomaly) {
+	cecontext.LoggerFrom(ctx).Warnw("sequence anomaly detected",
+		"kind", a.Kind.String(), "source", a.Source, "expected", a.Expected, "sequence", a.Sequence, "missing", a.Missing)
+}
+
+// Track checks the sequence of e against the last sequence received from the same source.
+// It implements client.InboundEventInterceptor. Events without an Integer sequence are ignored.
+// With a reorder window, the completion of the invocation of an event is observed through ctx, which the client
+// cancels when the invocation completes: the next event of the source isn't returned before.
+func (t *Tracker) Track(ctx context.Context, e *event.Event) (context.Context, error) {
+	ext, ok := extensions.GetSequenceExtension(*e)
+	if !ok {
+		return ctx, nil
+	}
+	seq, err := ext.Integer()
+	if err != nil {
+		return ctx, nil
+	}
+
+	source := e.Source()
+
+	t.mu.Lock()
+	s, ok := t.sources[source]
+	if !ok {
+		// First event from this source, nothing to compare with
+		t.evict()
+		s = &sourceState{
+			source:  source,
+			waiting: make(map[int32]*heldEvent),
+		}
+		s.element = t.recent.PushFront(s)
+		t.sources[source] = s
+		previous := t.admit(s, seq, ctx.Done())
+		t.mu.Unlock()
+		return ctx, waitPrevious(ctx, previous)
+	}
+	t.recent.MoveToFront(s.element)
+
+	d := distance(s.next, seq)
+	switch {
+	case d == 0:
+		previous := t.admit(s, seq, ctx.Done())
+		t.release(s)
+		t.mu.Unlock()
+		return ctx, waitPrevious(ctx, previous)
+	case d >= sequenceSize/2:
+		kind := Duplicate
+		if s.removeSkipped(seq) {
+			kind = OutOfOrder
+		}
+		anomaly := Anomaly{Kind: kind, Source: source, Expected: s.next, Sequence: seq}
+		t.mu.Unlock()
+		t.report(ctx, anomaly)
+		if kind == Duplicate && t.dropDuplicates {
+			return ctx, protocol.ResultACK
+		}
+		return ctx, nil
+	}
+
+	// seq is ahead of the expected one
+	if _, ok := s.waiting[seq]; ok {
+		anomaly := Anomaly{Kind: Duplicate, Source: source, Expected: s.next, Sequence: seq}
+		t.mu.Unlock()
+		t.report(ctx, anomaly)
+		if t.dropDuplicates {
+			return ctx, protocol.ResultACK
+		}
+		return ctx, nil
+	}
+	if t.windowSize <= 0 {
+		anomaly := t.skipTo(s, source, seq)
+		t.admit(s, seq, nil)
+		t.mu.Unlock()
+		t.report(ctx, anomaly)
+		return ctx, nil
+	}
+
+	h := &heldEvent{released: make(chan struct{}), done: ctx.Done()}
+	s.waiting[seq] = h
+	var anomalies []Anomaly
+	if len(s.waiting) > t.windowSize {
+		anomalies = append(anomalies, t.skipTo(s, source, s.firstWaiting()))
+		t.release(s)
+	}
+	t.mu.Unlock()
+	for _, a := range anomalies {
+		t.report(ctx, a)
+	}
+
+	timer := time.NewTimer(t.windowTimeout)
+	defer timer.Stop()
+	select {
+	case <-h.released:
+	case <-timer.C:
+		t.mu.Lock()
+		var anomaly *Anomaly
+		if s.waiting[seq] == h {
+			a := t.skipTo(s, source, seq)
+			anomaly = &a
+			t.release(s)
+		}
+		t.mu.Unlock()
+		if anomaly != nil {
+			t.report(ctx, *anomaly)
+		}
+	case <-ctx.Done():
+		t.mu.Lock()
+		if s.waiting[seq] == h {
+			delete(s.waiting, seq)
+		}
+		t.mu.Unlock()
+		return ctx, ctx.Err()
+	}
+	// previous is set before released is closed, within the lock
+	t.mu.Lock()
+	previous := h.previous
+	t.mu.Unlock()
+	return ctx, waitPrevious(ctx, previous)
+}
+
+// waitPrevious waits for the invocation of the previous event of the source to complete, if any
+func waitPrevious(ctx context.Context, previous <-chan struct{}) error {
+	if previous == nil {
+		return nil
+	}
+	select {
+	case <-previous:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// admit hands the event seq, whose invocation completes when done is closed, to the receiver. It returns the
+// channel closed when the invocation of the previous event completes. Must be invoked holding the lock.
+func (t *Tracker) admit(s *sourceState, seq int32, done <-chan struct{}) <-chan struct{} {
+	previous := s.last
+	s.last = nil
+	if t.windowSize > 0 {
+		s.last = done
+	}
+	s.next = extensions.NextIntegerSequence(seq)
+	return previous
+}
+
+// admitHeld admits the held back event seq and releases it. Must be invoked holding the lock.
+func (t *Tracker) admitHeld(s *sourceState, seq int32) {
+	h := s.waiting[seq]
+	delete(s.waiting, seq)
+	h.previous = t.admit(s, seq, h.done)
+	close(h.released)
+}
+
+// skipTo moves the expected sequence of s to target, releasing in order the held back events on the way
+// and remembering the missing values. Must be invoked holding the lock.
+func (t *Tracker) skipTo(s *sourceState, source string, target int32) Anomaly {
+	anomaly := Anomaly{Kind: Gap, Source: source, Expected: s.next, Sequence: target}
+	missing := distance(s.next, target)
+	if missing == 0 || missing >= sequenceSize/2 {
+		return anomaly
+	}
+
+	held := make([]int32, 0, len(s.waiting))
+	for seq := range s.waiting {
+		if distance(s.next, seq) < missing {
+			held = append(held, seq)
+		}
+	}
+	sort.Slice(held, func(i, j int) bool { return distance(s.next, held[i]) < distance(s.next, held[j]) })
+	for _, seq := range held {
+		s.addSkipped(s.next, distance(s.next, seq))
+		t.admitHeld(s, seq)
+		missing--
+	}
+	s.addSkipped(s.next, distance(s.next, target))
+	anomaly.Missing = int(missing)
+	s.next = target
+	return anomaly
+}
+
+// release releases in order the held back events following the expected sequence. Must be invoked holding the lock.
+func (t *Tracker) release(s *sourceState) {
+	for {
+		if _, ok := s.waiting[s.next]; !ok {
+			return
+		}
+		t.admitHeld(s, s.next)
+	}
+}
+
+// firstWaiting returns the first held back sequence value following the expected one
+func (s *sourceState) firstWaiting() int32 {
+	first, firstDistance := s.next, int64(-1)
+	for seq := range s.waiting {
+		if d := distance(s.next, seq); firstDistance < 0 || d < firstDistance {
+			first, firstDistance = seq, d
+		}
+	}
+	return first
+}
+
+// addSkipped remembers the length missing values from start, forgetting the oldest ranges beyond maxSkipped
+func (s *sourceState) addSkipped(start int32, length int64) {
+	if length <= 0 {
+		return
+	}
+	if n := len(s.skipped); n > 0 && advance(s.skipped[n-1].start, s.skipped[n-1].length) == start {
+		s.skipped[n-1].length += length
+		return
+	}
+	if len(s.skipped) >= maxSkipped {
+		s.skipped = s.skipped[1:]
+	}
+	s.skipped = append(s.skipped, sequenceRange{start: start, length: length})
+}
+
+// removeSkipped forgets seq if it was reported as missing, returning true in that case
+func (s *sourceState) removeSkipped(seq int32) bool {
+	for i, r := range s.skipped {
+		if !r.contains(seq) {
+			continue
+		}
+		before := sequenceRange{start: r.start, length: distance(r.start, seq)}
+		after := sequenceRange{start: advance(seq, 1), length: r.length - before.length - 1}
+		var split []sequenceRange
+		for _, part := range []sequenceRange{before, after} {
+			if part.length > 0 {
+				split = append(split, part)
+			}
+		}
+		s.skipped = append(s.skipped[:i], append(split, s.skipped[i+1:]...)...)
+		return true
+	}
+	return false
+}
+
+// evict forgets the least recently seen sources until a new source can be tracked. The sources holding back
+// events aren't forgotten. Must be invoked holding the lock.
+func (t *Tracker) evict() {
+	for e := t.recent.Back(); e != nil && len(t.sources) >= t.maxSources; {
+		prev := e.Prev()
+		if s := e.Value.(*sourceState); len(s.waiting) == 0 {
+			t.recent.Remove(e)
+			delete(t.sources, s.source)
+		}
+		e = prev
+	}
+}
+
+// WithSequenceTracking adds an inbound event interceptor, backed by a new Tracker, to the client.
+func WithSequenceTracking(opts ...TrackerOption) client.Option {
+	return client.WithInboundEventInterceptor(NewTracker(opts...).Track)
+}
@@ -0,0 +1,227 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package sequence_test
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/extensions/sequence"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+type recorder struct {
+	mu        sync.Mutex
+	anomalies []sequence.Anomaly
+}
+
+func (r *recorder) report(_ context.Context, a sequence.Anomaly) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.anomalies = append(r.anomalies, a)
+}
+
+func (r *recorder) get() []sequence.Anomaly {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sequence.Anomaly(nil), r.anomalies...)
+}
+
+func sequencedEvent(seq int32) *event.Event {
+	e := test.MinEvent()
+	extensions.NewIntegerSequenceExtension(seq).AddSequenceAttributes(&e)
+	return &e
+}
+
+func TestTracker(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []sequence.TrackerOption
+		sequences  []int32
+		want       []sequence.Anomaly
+		wantResult []error
+	}{{
+		name:       "in order",
+		sequences:  []int32{1, 2, 3},
+		wantResult: []error{nil, nil, nil},
+	}, {
+		name:       "gap",
+		sequences:  []int32{1, 2, 5, 6},
+		want:       []sequence.Anomaly{{Kind: sequence.Gap, Source: test.Source.String(), Expected: 3, Sequence: 5, Missing: 2}},
+		wantResult: []error{nil, nil, nil, nil},
+	}, {
+		name:      "duplicate",
+		sequences: []int32{1, 2, 2, 3},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Duplicate, Source: test.Source.String(), Expected: 3, Sequence: 2},
+		},
+		wantResult: []error{nil, nil, nil, nil},
+	}, {
+		name:      "drop duplicate",
+		opts:      []sequence.TrackerOption{sequence.WithDropDuplicates()},
+		sequences: []int32{1, 2, 2, 3},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Duplicate, Source: test.Source.String(), Expected: 3, Sequence: 2},
+		},
+		wantResult: []error{nil, nil, protocol.ResultACK, nil},
+	}, {
+		name:      "out of order",
+		sequences: []int32{1, 3, 2},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Gap, Source: test.Source.String(), Expected: 2, Sequence: 3, Missing: 1},
+			{Kind: sequence.OutOfOrder, Source: test.Source.String(), Expected: 4, Sequence: 2},
+		},
+		wantResult: []error{nil, nil, nil},
+	}, {
+		name:      "large gap",
+		sequences: []int32{1, 5000, 2, 4999, 2},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Gap, Source: test.Source.String(), Expected: 2, Sequence: 5000, Missing: 4998},
+			{Kind: sequence.OutOfOrder, Source: test.Source.String(), Expected: 5001, Sequence: 2},
+			{Kind: sequence.OutOfOrder, Source: test.Source.String(), Expected: 5001, Sequence: 4999},
+			{Kind: sequence.Duplicate, Source: test.Source.String(), Expected: 5001, Sequence: 2},
+		},
+		wantResult: []error{nil, nil, nil, nil, nil},
+	}, {
+		name:       "roll over",
+		sequences:  []int32{math.MaxInt32 - 1, math.MaxInt32, 1, 2},
+		wantResult: []error{nil, nil, nil, nil},
+	}, {
+		name:      "gap over roll over",
+		sequences: []int32{math.MaxInt32 - 1, 2, math.MaxInt32},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Gap, Source: test.Source.String(), Expected: math.MaxInt32, Sequence: 2, Missing: 2},
+			{Kind: sequence.OutOfOrder, Source: test.Source.String(), Expected: 3, Sequence: math.MaxInt32},
+		},
+		wantResult: []error{nil, nil, nil},
+	}, {
+		name:      "duplicate before roll over",
+		sequences: []int32{1, math.MaxInt32 - 1},
+		want: []sequence.Anomaly{
+			{Kind: sequence.Duplicate, Source: test.Source.String(), Expected: 2, Sequence: math.MaxInt32 - 1},
+		},
+		wantResult: []error{nil, nil},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			tracker := sequence.NewTracker(append(tt.opts, sequence.WithReportFunc(r.report))...)
+			for i, seq := range tt.sequences {
+				_, err := tracker.Track(context.TODO(), sequencedEvent(seq))
+				require.Equal(t, tt.wantResult[i], err)
+			}
+			require.Equal(t, tt.want, r.get())
+		})
+	}
+}
+
+func TestTracker_MaxSources(t *testing.T) {
+	r := &recorder{}
+	tracker := sequence.NewTracker(sequence.WithReportFunc(r.report), sequence.WithMaxSources(2))
+	track := func(source string, seq int32) {
+		e := sequencedEvent(seq)
+		e.SetSource(source)
+		_, err := tracker.Track(context.TODO(), e)
+		require.NoError(t, err)
+	}
+
+	track("a", 1)
+	track("b", 1)
+	track("a", 2)
+	// b is the least recently seen source, so it's forgotten, while a is still tracked
+	track("c", 1)
+	track("a", 5)
+	track("b", 5)
+	require.Equal(t, []sequence.Anomaly{{Kind: sequence.Gap, Source: "a", Expected: 3, Sequence: 5, Missing: 2}}, r.get())
+}
+
+func TestTracker_ReorderWindow(t *testing.T) {
+	r := &recorder{}
+	tracker := sequence.NewTracker(sequence.WithReportFunc(r.report), sequence.WithReorderWindow(10, time.Minute))
+
+	_, err := tracker.Track(context.TODO(), sequencedEvent(1))
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var invoked []int32
+	// invoke simulates an invocation of the receiver, which cancels the context once it completes
+	invoke := func(seq int32) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		_, err := tracker.Track(ctx, sequencedEvent(seq))
+		require.NoError(t, err)
+		mu.Lock()
+		invoked = append(invoked, seq)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	for _, seq := range []int32{4, 3} {
+		seq := seq
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			invoke(seq)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	require.Empty(t, invoked, "events ahead of the sequence must be held back")
+	mu.Unlock()
+
+	invoke(2)
+	wg.Wait()
+	require.Equal(t, []int32{2, 3, 4}, invoked)
+	require.Empty(t, r.get())
+
+	invoke(5)
+	require.Equal(t, []int32{2, 3, 4, 5}, invoked)
+}
+
+func TestTracker_ReorderWindowTimeout(t *testing.T) {
+	r := &recorder{}
+	tracker := sequence.NewTracker(sequence.WithReportFunc(r.report), sequence.WithReorderWindow(10, 10*time.Millisecond))
+
+	_, err := tracker.Track(context.TODO(), sequencedEvent(1))
+	require.NoError(t, err)
+	_, err = tracker.Track(context.TODO(), sequencedEvent(3))
+	require.NoError(t, err)
+
+	require.Equal(t, []sequence.Anomaly{{Kind: sequence.Gap, Source: test.Source.String(), Expected: 2, Sequence: 3, Missing: 1}}, r.get())
+}
+
+func TestTracker_ReorderWindowFull(t *testing.T) {
+	r := &recorder{}
+	tracker := sequence.NewTracker(sequence.WithReportFunc(r.report), sequence.WithReorderWindow(1, time.Minute))
+
+	_, err := tracker.Track(context.TODO(), sequencedEvent(1))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		_, err := tracker.Track(context.TODO(), sequencedEvent(3))
+		require.NoError(t, err)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// The window is full, so 3 is released and 4 passes through
+	_, err = tracker.Track(context.TODO(), sequencedEvent(4))
+	require.NoError(t, err)
+	<-done
+
+	require.Equal(t, []sequence.Anomaly{{Kind: sequence.Gap, Source: test.Source.String(), Expected: 2, Sequence: 3, Missing: 1}}, r.get())
+}
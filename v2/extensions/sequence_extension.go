@@ -0,0 +1,80 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	SequenceExtensionKey     = "sequence"
+	SequenceTypeExtensionKey = "sequencetype"
+
+	// SequenceTypeInteger is the sequencetype of sequences encoded as signed 32-bit integers,
+	// starting from 1 and rolling over to 1 after 2,147,483,647.
+	SequenceTypeInteger = "Integer"
+)
+
+// SequenceExtension represents the sequence extension for cloudevents context.
+// See https://github.com/cloudevents/spec/blob/v1.0.1/extensions/sequence.md
+type SequenceExtension struct {
+	Sequence     string `json:"sequence"`
+	SequenceType string `json:"sequencetype"`
+}
+
+// NewIntegerSequenceExtension returns a SequenceExtension with sequencetype Integer
+func NewIntegerSequenceExtension(seq int32) SequenceExtension {
+	return SequenceExtension{Sequence: strconv.FormatInt(int64(seq), 10), SequenceType: SequenceTypeInteger}
+}
+
+// AddSequenceAttributes adds the sequence and sequencetype attributes to the cloudevents context
+func (s SequenceExtension) AddSequenceAttributes(e event.EventWriter) {
+	if s.Sequence != "" {
+		e.SetExtension(SequenceExtensionKey, s.Sequence)
+		if s.SequenceType != "" {
+			e.SetExtension(SequenceTypeExtensionKey, s.SequenceType)
+		}
+	}
+}
+
+// Integer returns the sequence as integer, if the sequencetype is Integer
+func (s SequenceExtension) Integer() (int32, error) {
+	if s.SequenceType != SequenceTypeInteger {
+		return 0, fmt.Errorf("sequencetype %q is not %q", s.SequenceType, SequenceTypeInteger)
+	}
+	i, err := strconv.ParseInt(s.Sequence, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Integer sequence %q: %w", s.Sequence, err)
+	}
+	return int32(i), nil
+}
+
+// NextIntegerSequence returns the Integer sequence value following seq, rolling over to 1
+func NextIntegerSequence(seq int32) int32 {
+	if seq == math.MaxInt32 {
+		return 1
+	}
+	return seq + 1
+}
+
+// GetSequenceExtension returns the sequence extension of the event, if any
+func GetSequenceExtension(e event.Event) (SequenceExtension, bool) {
+	if seq, ok := e.Extensions()[SequenceExtensionKey]; ok {
+		if seqStr, err := types.ToString(seq); err == nil {
+			var typeStr string
+			if st, ok := e.Extensions()[SequenceTypeExtensionKey]; ok {
+				typeStr, _ = types.ToString(st)
+			}
+			return SequenceExtension{Sequence: seqStr, SequenceType: typeStr}, true
+		}
+	}
+	return SequenceExtension{}, false
+}
@@ -0,0 +1,42 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package extensions_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestSequenceExtension(t *testing.T) {
+	e := test.MinEvent()
+
+	_, ok := extensions.GetSequenceExtension(e)
+	require.False(t, ok)
+
+	extensions.NewIntegerSequenceExtension(42).AddSequenceAttributes(&e)
+	got, ok := extensions.GetSequenceExtension(e)
+	require.True(t, ok)
+	require.Equal(t, extensions.SequenceTypeInteger, got.SequenceType)
+
+	seq, err := got.Integer()
+	require.NoError(t, err)
+	require.Equal(t, int32(42), seq)
+
+	_, err = extensions.SequenceExtension{Sequence: "abc", SequenceType: extensions.SequenceTypeInteger}.Integer()
+	require.Error(t, err)
+	_, err = extensions.SequenceExtension{Sequence: "1"}.Integer()
+	require.Error(t, err)
+}
+
+func TestNextIntegerSequence(t *testing.T) {
+	require.Equal(t, int32(2), extensions.NextIntegerSequence(1))
+	require.Equal(t, int32(1), extensions.NextIntegerSequence(math.MaxInt32))
+}
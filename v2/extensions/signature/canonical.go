@@ -0,0 +1,76 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// Canonicalize returns the canonical form of the event signed by the Signer.
+// The canonical form is made of the context attributes and the provided extensions, sorted by name,
+// each one formatted as a canonical string, followed by the SHA-256 digest of the data.
+// Because every value is compared in its string form, the canonical form is the same
+// whether the event was transferred in binary or structured mode.
+func Canonicalize(e event.Event, extensionNames []string) ([]byte, error) {
+	if e.Context == nil {
+		return nil, fmt.Errorf("event has no context")
+	}
+	version := spec.VS.Version(e.SpecVersion())
+	if version == nil {
+		return nil, fmt.Errorf("unsupported specversion %q", e.SpecVersion())
+	}
+
+	values := make(map[string]string)
+	for _, attr := range version.Attributes() {
+		v := attr.Get(e.Context)
+		if types.IsZero(v) {
+			continue
+		}
+		s, err := types.Format(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format attribute %q: %w", attr.Name(), err)
+		}
+		values[attr.Name()] = s
+	}
+	for _, name := range extensionNames {
+		v, ok := e.Extensions()[name]
+		if !ok {
+			return nil, fmt.Errorf("signed extension %q is missing", name)
+		}
+		s, err := types.Format(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format extension %q: %w", name, err)
+		}
+		values[name] = s
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(strconv.Quote(values[name]))
+		buf.WriteByte('\n')
+	}
+	digest := sha256.Sum256(e.Data())
+	buf.WriteString("\n")
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(digest[:]))
+	return buf.Bytes(), nil
+}
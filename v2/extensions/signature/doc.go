@@ -0,0 +1,9 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package signature implements end to end integrity of events: a Signer stores a detached JWS,
+// computed over a canonical form of the attributes and data, in the signature extension,
+// and a Verifier rejects received events with a missing or invalid signature.
+package signature
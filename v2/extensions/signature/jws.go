@@ -0,0 +1,153 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var errInvalidSignature = errors.New("invalid signature")
+
+var errES256Curve = errors.New("ES256 requires a P-256 key")
+
+// header is the JWS protected header
+type header struct {
+	Algorithm Algorithm `json:"alg"`
+	KeyID     string    `json:"kid,omitempty"`
+	// Extensions lists the extensions covered by the signature
+	Extensions []string `json:"exts"`
+}
+
+// signDetached returns the compact serialization of a JWS with detached payload: header..signature
+func signDetached(h header, key Key, payload []byte) (string, error) {
+	hb, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(hb)
+	sig, err := sign(key, signingInput(encodedHeader, payload))
+	if err != nil {
+		return "", err
+	}
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parseDetached parses the compact serialization of a JWS with detached payload
+func parseDetached(jws string) (h header, encodedHeader string, sig []byte, err error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return h, "", nil, fmt.Errorf("malformed detached JWS")
+	}
+	hb, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return h, "", nil, fmt.Errorf("malformed JWS header: %w", err)
+	}
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return h, "", nil, fmt.Errorf("malformed JWS header: %w", err)
+	}
+	sig, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return h, "", nil, fmt.Errorf("malformed JWS signature: %w", err)
+	}
+	return h, parts[0], sig, nil
+}
+
+func signingInput(encodedHeader string, payload []byte) []byte {
+	return []byte(encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload))
+}
+
+func sign(key Key, input []byte) ([]byte, error) {
+	switch key.Algorithm {
+	case EdDSA:
+		k, ok := key.Key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("EdDSA requires an ed25519.PrivateKey, got %T", key.Key)
+		}
+		return ed25519.Sign(k, input), nil
+	case ES256:
+		k, ok := key.Key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("ES256 requires an *ecdsa.PrivateKey, got %T", key.Key)
+		}
+		if k.Curve != elliptic.P256() {
+			return nil, errES256Curve
+		}
+		digest := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed size concatenation of r and s
+		sig := make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(sig[32-len(rb):32], rb)
+		copy(sig[64-len(sb):], sb)
+		return sig, nil
+	case HS256:
+		k, ok := key.Key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("HS256 requires a []byte secret, got %T", key.Key)
+		}
+		mac := hmac.New(crypto.SHA256.New, k)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q", key.Algorithm)
+}
+
+func verify(key Key, input []byte, sig []byte) error {
+	switch key.Algorithm {
+	case EdDSA:
+		k, ok := key.Key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("EdDSA requires an ed25519.PublicKey, got %T", key.Key)
+		}
+		if !ed25519.Verify(k, input, sig) {
+			return errInvalidSignature
+		}
+		return nil
+	case ES256:
+		k, ok := key.Key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("ES256 requires an *ecdsa.PublicKey, got %T", key.Key)
+		}
+		if k.Curve != elliptic.P256() {
+			return errES256Curve
+		}
+		if len(sig) != 64 {
+			return errInvalidSignature
+		}
+		digest := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return errInvalidSignature
+		}
+		return nil
+	case HS256:
+		expected, err := sign(key, input)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(expected, sig) {
+			return errInvalidSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %q", key.Algorithm)
+}
@@ -0,0 +1,116 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Algorithm is a JWS signature algorithm.
+type Algorithm string
+
+const (
+	// EdDSA is the Ed25519 signature algorithm.
+	EdDSA Algorithm = "EdDSA"
+	// ES256 is ECDSA using P-256 and SHA-256.
+	ES256 Algorithm = "ES256"
+	// HS256 is HMAC using SHA-256.
+	HS256 Algorithm = "HS256"
+)
+
+// Key is a signing or verification key.
+// Key.Key must be:
+// * ed25519.PrivateKey to sign and ed25519.PublicKey to verify with EdDSA
+// * *ecdsa.PrivateKey to sign and *ecdsa.PublicKey to verify with ES256, on the P-256 curve
+// * []byte, the shared secret, with HS256
+type Key struct {
+	ID        string
+	Algorithm Algorithm
+	Key       interface{}
+}
+
+// KeyProvider provides the keys to sign and verify events.
+type KeyProvider interface {
+	// SigningKey returns the key to sign the provided event.
+	SigningKey(ctx context.Context, e event.Event) (Key, error)
+	// VerificationKey returns the key with the provided id, to verify a signature computed with alg.
+	VerificationKey(ctx context.Context, id string, alg Algorithm) (Key, error)
+}
+
+// KeySet is a KeyProvider backed by a static set of keys.
+type KeySet struct {
+	signing      *Key
+	verification map[string]Key
+}
+
+// NewKeySet returns a KeySet that signs with the signing key, if not nil, and verifies with the verification keys.
+// The signing key, or its public key for asymmetric algorithms, is also used for verification.
+func NewKeySet(signing *Key, verification ...Key) (*KeySet, error) {
+	ks := &KeySet{signing: signing, verification: make(map[string]Key)}
+	if signing != nil {
+		vk, err := verificationKeyOf(*signing)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCurve(vk); err != nil {
+			return nil, err
+		}
+		ks.verification[vk.ID] = vk
+	}
+	for _, k := range verification {
+		if err := checkCurve(k); err != nil {
+			return nil, err
+		}
+		ks.verification[k.ID] = k
+	}
+	return ks, nil
+}
+
+// checkCurve returns an error if k is an ES256 key on another curve than P-256
+func checkCurve(k Key) error {
+	if pk, ok := k.Key.(*ecdsa.PublicKey); ok && k.Algorithm == ES256 && pk.Curve != elliptic.P256() {
+		return fmt.Errorf("key %q: %w", k.ID, errES256Curve)
+	}
+	return nil
+}
+
+func (ks *KeySet) SigningKey(context.Context, event.Event) (Key, error) {
+	if ks.signing == nil {
+		return Key{}, fmt.Errorf("no signing key configured")
+	}
+	return *ks.signing, nil
+}
+
+func (ks *KeySet) VerificationKey(_ context.Context, id string, alg Algorithm) (Key, error) {
+	k, ok := ks.verification[id]
+	if !ok {
+		return Key{}, fmt.Errorf("unknown key id %q", id)
+	}
+	if k.Algorithm != alg {
+		return Key{}, fmt.Errorf("key %q is not a %s key", id, alg)
+	}
+	return k, nil
+}
+
+var _ KeyProvider = (*KeySet)(nil)
+
+func verificationKeyOf(k Key) (Key, error) {
+	switch key := k.Key.(type) {
+	case ed25519.PrivateKey:
+		return Key{ID: k.ID, Algorithm: k.Algorithm, Key: key.Public()}, nil
+	case *ecdsa.PrivateKey:
+		return Key{ID: k.ID, Algorithm: k.Algorithm, Key: &key.PublicKey}, nil
+	case []byte:
+		return k, nil
+	}
+	return Key{}, fmt.Errorf("unsupported signing key type %T", k.Key)
+}
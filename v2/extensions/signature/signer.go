@@ -0,0 +1,115 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const SignatureExtensionKey = "signature"
+
+// Signer signs events with the keys of a KeyProvider.
+type Signer struct {
+	keys KeyProvider
+}
+
+// NewSigner returns a Signer using the signing keys of keys.
+func NewSigner(keys KeyProvider) *Signer {
+	return &Signer{keys: keys}
+}
+
+// Sign computes the detached JWS of e and stores it in the signature extension.
+// All the extensions of e at signing time are covered by the signature.
+// It implements client.OutboundEventInterceptor.
+func (s *Signer) Sign(ctx context.Context, e *event.Event) error {
+	key, err := s.keys.SigningKey(ctx, *e)
+	if err != nil {
+		return fmt.Errorf("failed to get signing key: %w", err)
+	}
+
+	exts := make([]string, 0, len(e.Extensions()))
+	for name := range e.Extensions() {
+		if name != SignatureExtensionKey {
+			exts = append(exts, name)
+		}
+	}
+	sort.Strings(exts)
+
+	payload, err := Canonicalize(*e, exts)
+	if err != nil {
+		return err
+	}
+	jws, err := signDetached(header{Algorithm: key.Algorithm, KeyID: key.ID, Extensions: exts}, key, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign event: %w", err)
+	}
+
+	e.Context = e.Context.Clone()
+	return e.Context.SetExtension(SignatureExtensionKey, jws)
+}
+
+// Verifier verifies the signatures of events with the keys of a KeyProvider.
+type Verifier struct {
+	keys KeyProvider
+}
+
+// NewVerifier returns a Verifier using the verification keys of keys.
+func NewVerifier(keys KeyProvider) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// VerifyEvent returns an error if the signature of e is missing or invalid.
+// Extensions not covered by the signature, e.g. added by intermediaries, are ignored.
+func (v *Verifier) VerifyEvent(ctx context.Context, e event.Event) error {
+	ext, ok := e.Extensions()[SignatureExtensionKey]
+	if !ok {
+		return fmt.Errorf("missing %s extension", SignatureExtensionKey)
+	}
+	jws, err := types.ToString(ext)
+	if err != nil {
+		return err
+	}
+
+	h, encodedHeader, sig, err := parseDetached(jws)
+	if err != nil {
+		return err
+	}
+	key, err := v.keys.VerificationKey(ctx, h.KeyID, h.Algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to get verification key: %w", err)
+	}
+	payload, err := Canonicalize(e, h.Extensions)
+	if err != nil {
+		return err
+	}
+	return verify(key, signingInput(encodedHeader, payload), sig)
+}
+
+// Verify rejects e as malformed if its signature is missing or invalid.
+// It implements client.InboundEventInterceptor.
+func (v *Verifier) Verify(ctx context.Context, e *event.Event) (context.Context, error) {
+	if err := v.VerifyEvent(ctx, *e); err != nil {
+		return ctx, client.NewMalformedEventResult(fmt.Errorf("signature verification failed: %w", err))
+	}
+	return ctx, nil
+}
+
+// WithSigning adds an outbound event interceptor signing the events to the client.
+// Add it after the interceptors modifying the events, so the signature covers their changes.
+func WithSigning(keys KeyProvider) client.Option {
+	return client.WithOutboundEventInterceptor(NewSigner(keys).Sign)
+}
+
+// WithVerification adds an inbound event interceptor verifying the signature of the events to the client.
+func WithVerification(keys KeyProvider) client.Option {
+	return client.WithInboundEventInterceptor(NewVerifier(keys).Verify)
+}
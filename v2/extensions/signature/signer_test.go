@@ -0,0 +1,135 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package signature_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	clienttest "github.com/cloudevents/sdk-go/v2/client/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions/signature"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func signingKeys(t *testing.T) map[string]signature.Key {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return map[string]signature.Key{
+		"EdDSA": {ID: "ed", Algorithm: signature.EdDSA, Key: edKey},
+		"ES256": {ID: "ec", Algorithm: signature.ES256, Key: ecKey},
+		"HS256": {ID: "hs", Algorithm: signature.HS256, Key: []byte("a shared secret")},
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	for name, key := range signingKeys(t) {
+		key := key
+		t.Run(name, func(t *testing.T) {
+			keys, err := signature.NewKeySet(&key)
+			require.NoError(t, err)
+			signer := signature.NewSigner(keys)
+			verifier := signature.NewVerifier(keys)
+
+			test.EachEvent(t, test.Events(), func(t *testing.T, e event.Event) {
+				e = test.ConvertEventExtensionsToString(t, e.Clone())
+				original := e.Clone()
+				require.NoError(t, signer.Sign(context.TODO(), &e))
+				require.NotContains(t, original.Extensions(), signature.SignatureExtensionKey)
+				require.NoError(t, verifier.VerifyEvent(context.TODO(), e))
+
+				// The signature survives the conversion to binary and structured messages
+				for _, m := range []binding.Message{bindingtest.MustCreateMockBinaryMessage(e), bindingtest.MustCreateMockStructuredMessage(t, e)} {
+					got, err := binding.ToEvent(context.TODO(), m)
+					require.NoError(t, err)
+					require.NoError(t, verifier.VerifyEvent(context.TODO(), *got))
+				}
+
+				// Extensions added after signing are ignored
+				extended := e.Clone()
+				extended.SetExtension("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+				require.NoError(t, verifier.VerifyEvent(context.TODO(), extended))
+
+				tampered := e.Clone()
+				tampered.SetSubject("tampered")
+				require.Error(t, verifier.VerifyEvent(context.TODO(), tampered))
+
+				tampered = e.Clone()
+				tampered.DataEncoded = []byte("tampered")
+				require.Error(t, verifier.VerifyEvent(context.TODO(), tampered))
+
+				require.Error(t, verifier.VerifyEvent(context.TODO(), original))
+			})
+		})
+	}
+}
+
+func TestVerify_UnknownKey(t *testing.T) {
+	keys := signingKeys(t)
+	signing := keys["EdDSA"]
+	signingKeySet, err := signature.NewKeySet(&signing)
+	require.NoError(t, err)
+	otherKeySet, err := signature.NewKeySet(nil, keys["HS256"])
+	require.NoError(t, err)
+
+	e := test.FullEvent()
+	require.NoError(t, signature.NewSigner(signingKeySet).Sign(context.TODO(), &e))
+
+	_, err = signature.NewVerifier(otherKeySet).Verify(context.TODO(), &e)
+	require.True(t, protocol.IsNACK(err))
+}
+
+// staticKeyProvider returns its key without any check
+type staticKeyProvider signature.Key
+
+func (k staticKeyProvider) SigningKey(context.Context, event.Event) (signature.Key, error) {
+	return signature.Key(k), nil
+}
+
+func (k staticKeyProvider) VerificationKey(context.Context, string, signature.Algorithm) (signature.Key, error) {
+	return signature.Key(k), nil
+}
+
+func TestES256_OtherCurve(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	key := signature.Key{ID: "ec", Algorithm: signature.ES256, Key: ecKey}
+
+	_, err = signature.NewKeySet(&key)
+	require.EqualError(t, err, `key "ec": ES256 requires a P-256 key`)
+	_, err = signature.NewKeySet(nil, signature.Key{ID: "ec", Algorithm: signature.ES256, Key: &ecKey.PublicKey})
+	require.EqualError(t, err, `key "ec": ES256 requires a P-256 key`)
+
+	e := test.FullEvent()
+	err = signature.NewSigner(staticKeyProvider(key)).Sign(context.TODO(), &e)
+	require.EqualError(t, err, "failed to sign event: ES256 requires a P-256 key")
+}
+
+func TestWithSigningAndVerification(t *testing.T) {
+	key := signingKeys(t)["EdDSA"]
+	keys, err := signature.NewKeySet(&key)
+	require.NoError(t, err)
+
+	in := test.FullEvent()
+	clienttest.SendReceive(t, func() interface{} {
+		return gochan.New()
+	}, in, func(out event.Event) {
+		_, ok := out.Extensions()[signature.SignatureExtensionKey]
+		require.True(t, ok)
+	}, signature.WithSigning(keys), signature.WithVerification(keys))
+}
@@ -16,4 +16,6 @@ const (
 	SourceAttr          = "cloudevents.source"
 	SubjectAttr         = "cloudevents.subject"
 	DatacontenttypeAttr = "cloudevents.datacontenttype"
+	CausationidAttr     = "cloudevents.causationid"
+	CorrelationidAttr   = "cloudevents.correlationid"
 )
@@ -7,11 +7,19 @@ package http
 
 import (
 	"context"
-	cecontext "github.com/cloudevents/sdk-go/v2/context"
-	"go.uber.org/zap"
+	"errors"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 )
 
 type WebhookConfig struct {
@@ -25,13 +33,107 @@ const (
 	DefaultAllowedRate = 1000
 )
 
-// TODO: implement rate limiting.
-// Throttling is indicated by requests being rejected using HTTP status code 429 Too Many Requests.
-// TODO: use this if Webhook Request Origin has been turned on.
-// Inbound requests should be rejected if Allowed Origins is required by SDK.
+var (
+	// ErrOriginNotAllowed is reported when a request is rejected because its origin is not in WebhookConfig.AllowedOrigins
+	ErrOriginNotAllowed = errors.New("webhook origin not allowed")
+	// ErrRateLimited is reported when a request is rejected because its origin exceeded WebhookConfig.AllowedRate
+	ErrRateLimited = errors.New("webhook allowed rate exceeded")
+)
+
+// maxRateLimiters is the number of tracked origins above which the idle ones are forgotten
+const maxRateLimiters = 1024
+
+// rateLimiters holds a rateLimiter for each origin sending requests
+type rateLimiters struct {
+	mu        sync.Mutex
+	limiters  map[string]*rateLimiter
+	nextPrune int
+}
+
+func (r *rateLimiters) allow(key string, perMinute int, now time.Time) (bool, time.Duration) {
+	r.mu.Lock()
+	l, ok := r.limiters[key]
+	if !ok {
+		if r.limiters == nil {
+			r.limiters = make(map[string]*rateLimiter)
+		}
+		if len(r.limiters) >= r.nextPrune {
+			r.prune(now)
+		}
+		// Allow a burst of one second of requests
+		l = newRateLimiter(perMinute, perMinute/60)
+		r.limiters[key] = l
+	}
+	r.mu.Unlock()
+	return l.allow(now)
+}
+
+// prune forgets the limiters which are full again, since they behave like new ones. r.mu must be held.
+func (r *rateLimiters) prune(now time.Time) {
+	for key, l := range r.limiters {
+		l.mu.Lock()
+		l.refill(now)
+		full := l.tokens >= l.burst
+		l.mu.Unlock()
+		if full {
+			delete(r.limiters, key)
+		}
+	}
+	r.nextPrune = 2 * len(r.limiters)
+	if r.nextPrune < maxRateLimiters {
+		r.nextPrune = maxRateLimiters
+	}
+}
+
+// checkWebhookRequest enforces WebhookConfig on a request delivering an event: its origin must be allowed
+// if WebhookConfig.AllowedOrigins is set, and its rate must not exceed WebhookConfig.AllowedRate if set.
+// The origin is read from the WebHook-Request-Origin header, or from the Origin header. The rate is limited per origin,
+// or per client IP when the request has no origin. Without AllowedOrigins, the origin is whatever the client sends:
+// a client changing it at each request bypasses the rate limit, so AllowedRate should be used with AllowedOrigins.
+// If the request is rejected, the response is written and the rejection reason is returned.
+func (p *Protocol) checkWebhookRequest(rw http.ResponseWriter, req *http.Request) error {
+	config, limiters := p.webhookConfigFor(req.Context())
+	origin := req.Header.Get("WebHook-Request-Origin")
+	if origin == "" {
+		origin = req.Header.Get("Origin")
+	}
+
+	if len(config.AllowedOrigins) > 0 {
+		if _, ok := matchOrigin(config, origin); !ok {
+			http.Error(rw, "Origin not allowed", http.StatusForbidden)
+			return ErrOriginNotAllowed
+		}
+	}
+
+	if config.AllowedRate != nil && *config.AllowedRate > 0 {
+		key := origin
+		if key == "" {
+			key = req.RemoteAddr
+			if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+				key = host
+			}
+		}
+		if ok, delay := limiters.allow(key, *config.AllowedRate, time.Now()); !ok {
+			rw.Header().Set(RetryAfter, strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(rw, "Too many requests", http.StatusTooManyRequests)
+			return ErrRateLimited
+		}
+	}
+	return nil
+}
+
+// reportRejection logs the rejection of a request and passes it to the rejection observers.
+// The rejections aren't returned by Receive, so that the rejected requests aren't reported as malformed events.
+func (p *Protocol) reportRejection(req *http.Request, err error) {
+	cecontext.LoggerFrom(req.Context()).Debugw("rejected webhook request", zap.Error(err), zap.String("remoteAddr", req.RemoteAddr))
+	for _, observe := range p.rejectionObservers {
+		observe(req, err)
+	}
+}
 
 func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
-	if req.Method != http.MethodOptions || p.WebhookConfig == nil {
+	config, _ := p.webhookConfigFor(req.Context())
+	if req.Method != http.MethodOptions || config == nil {
 		rw.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
@@ -53,21 +155,21 @@ func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
 		allowedRateRequired = true
 	}
 
-	if p.WebhookConfig.AllowedRate != nil {
-		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(*p.WebhookConfig.AllowedRate))
+	if config.AllowedRate != nil {
+		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(*config.AllowedRate))
 	} else if allowedRateRequired {
 		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(DefaultAllowedRate))
 	}
 
-	if len(p.WebhookConfig.AllowedMethods) > 0 {
-		headers.Set("Allow", strings.Join(p.WebhookConfig.AllowedMethods, ", "))
+	if len(config.AllowedMethods) > 0 {
+		headers.Set("Allow", strings.Join(config.AllowedMethods, ", "))
 	} else {
 		headers.Set("Allow", http.MethodPost)
 	}
 
 	cb := req.Header.Get("WebHook-Request-Callback")
 	if cb != "" {
-		if p.WebhookConfig.AutoACKCallback {
+		if config.AutoACKCallback {
 			go func() {
 				reqAck, err := http.NewRequest(http.MethodPost, cb, nil)
 				if err != nil {
@@ -101,26 +203,69 @@ func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
 }
 
 func (p *Protocol) ValidateRequestOrigin(req *http.Request) (string, bool) {
-	return p.validateOrigin(req.Header.Get("WebHook-Request-Origin"))
+	return p.validateOrigin(req, req.Header.Get("WebHook-Request-Origin"))
 }
 
 func (p *Protocol) ValidateOrigin(req *http.Request) (string, bool) {
-	return p.validateOrigin(req.Header.Get("Origin"))
+	return p.validateOrigin(req, req.Header.Get("Origin"))
 }
 
-func (p *Protocol) validateOrigin(ro string) (string, bool) {
+func (p *Protocol) validateOrigin(req *http.Request, ro string) (string, bool) {
 	cecontext.LoggerFrom(context.TODO()).Infow("Validating origin.", zap.String("origin", ro))
+	config, _ := p.webhookConfigFor(req.Context())
+	if config == nil {
+		return ro, false
+	}
+	return matchOrigin(config, ro)
+}
 
-	for _, ao := range p.WebhookConfig.AllowedOrigins {
+func matchOrigin(config *WebhookConfig, ro string) (string, bool) {
+	origin, ok := normalizeOrigin(ro)
+	for _, ao := range config.AllowedOrigins {
 		if ao == "*" {
 			return ao, true
 		}
-		// TODO: it is not clear what the rules for allowed hosts are.
-		// Need to find docs for this. For now, test for prefix.
-		if strings.HasPrefix(ro, ao) {
-			return ao, true
+		if allowed, allowedOk := normalizeOrigin(ao); ok && allowedOk && allowed.matches(origin) {
+			// WebHook-Allowed-Origin must be the request origin or "*"
+			return ro, true
 		}
 	}
 
 	return ro, false
 }
+
+// webhookOrigin is an origin normalized for comparison: its scheme is empty when the origin is a bare host name,
+// like the WebHook-Request-Origin header of the spec, and its host has no default port
+type webhookOrigin struct {
+	scheme string
+	host   string
+}
+
+// normalizeOrigin parses origin as scheme://host[:port] or as a bare host[:port]. Origins with a path, a query,
+// or user information aren't valid.
+func normalizeOrigin(origin string) (webhookOrigin, bool) {
+	origin = strings.TrimSpace(origin)
+	if origin == "" {
+		return webhookOrigin{}, false
+	}
+	if !strings.Contains(origin, "://") {
+		origin = "//" + origin
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return webhookOrigin{}, false
+	}
+	o := webhookOrigin{scheme: strings.ToLower(u.Scheme), host: strings.ToLower(u.Hostname())}
+	if port := u.Port(); port != "" && !(o.scheme == "http" && port == "80") && !(o.scheme == "https" && port == "443") {
+		o.host = net.JoinHostPort(o.host, port)
+	}
+	return o, true
+}
+
+// matches reports whether the request origin ro is the origin o. A bare host name matches any scheme.
+func (o webhookOrigin) matches(ro webhookOrigin) bool {
+	if o.host != ro.host {
+		return false
+	}
+	return o.scheme == "" || ro.scheme == "" || o.scheme == ro.scheme
+}
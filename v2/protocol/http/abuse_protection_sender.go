@@ -0,0 +1,133 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+)
+
+// ErrWebhookNotAllowed is returned when the delivery target refuses the webhook validation handshake.
+var ErrWebhookNotAllowed = errors.New("the delivery target did not grant the permission to send")
+
+// webhookValidator runs the validation handshake of the abuse protection spec before the first delivery to each target:
+// https://github.com/cloudevents/spec/blob/v1.0/http-webhook.md#4-abuse-protection
+type webhookValidator struct {
+	origin      string
+	requestRate int
+
+	mu      sync.Mutex
+	targets map[string]*webhookTarget
+}
+
+// webhookTarget holds the permission granted by a delivery target
+type webhookTarget struct {
+	mu            sync.Mutex
+	granted       bool
+	allowedOrigin string
+	// limiter is nil if the target doesn't limit the rate
+	limiter *rateLimiter
+}
+
+func newWebhookValidator(origin string, requestRate int) *webhookValidator {
+	return &webhookValidator{
+		origin:      origin,
+		requestRate: requestRate,
+		targets:     make(map[string]*webhookTarget),
+	}
+}
+
+func (v *webhookValidator) target(req *http.Request) *webhookTarget {
+	key := req.URL.String()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	t, ok := v.targets[key]
+	if !ok {
+		t = &webhookTarget{}
+		v.targets[key] = t
+	}
+	return t
+}
+
+// validate runs the handshake with the target of req, unless it already granted the permission.
+// The refusals are not cached, so the handshake is run again on the next delivery.
+func (v *webhookValidator) validate(ctx context.Context, client *http.Client, req *http.Request) error {
+	t := v.target(req)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.granted {
+		return nil
+	}
+
+	optionsReq, err := http.NewRequest(http.MethodOptions, req.URL.String(), nil)
+	if err != nil {
+		return err
+	}
+	optionsReq = optionsReq.WithContext(ctx)
+	optionsReq.Host = req.Host
+	copyHeadersEnsure(req.Header, &optionsReq.Header)
+	optionsReq.Header.Set("WebHook-Request-Origin", v.origin)
+	if v.requestRate > 0 {
+		optionsReq.Header.Set("WebHook-Request-Rate", strconv.Itoa(v.requestRate))
+	}
+
+	resp, err := client.Do(optionsReq)
+	if err != nil {
+		return fmt.Errorf("webhook validation handshake failed: %w", err)
+	}
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: the handshake returned status code %d", ErrWebhookNotAllowed, resp.StatusCode)
+	}
+	allowedOrigin := strings.TrimSpace(resp.Header.Get("WebHook-Allowed-Origin"))
+	// The allowed origin must be the request origin or "*"
+	if allowedOrigin != "*" && allowedOrigin != v.origin {
+		return fmt.Errorf("%w: the handshake returned the allowed origin %q", ErrWebhookNotAllowed, allowedOrigin)
+	}
+
+	var limiter *rateLimiter
+	if allowedRate := strings.TrimSpace(resp.Header.Get("WebHook-Allowed-Rate")); allowedRate != "" && allowedRate != "*" {
+		rate, err := strconv.Atoi(allowedRate)
+		if err != nil || rate <= 0 {
+			return fmt.Errorf("%w: the handshake returned the invalid allowed rate %q", ErrWebhookNotAllowed, allowedRate)
+		}
+		limiter = newRateLimiter(rate, 1)
+	}
+
+	cecontext.LoggerFrom(ctx).Debugw("webhook validation handshake succeeded",
+		zap.String("target", req.URL.String()),
+		zap.String("allowedOrigin", allowedOrigin),
+		zap.String("allowedRate", resp.Header.Get("WebHook-Allowed-Rate")))
+	t.granted = true
+	t.allowedOrigin = allowedOrigin
+	t.limiter = limiter
+	return nil
+}
+
+// throttle waits until req can be sent within the rate allowed by its target
+func (v *webhookValidator) throttle(req *http.Request) error {
+	t := v.target(req)
+	t.mu.Lock()
+	limiter := t.limiter
+	t.mu.Unlock()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(req.Context())
+}
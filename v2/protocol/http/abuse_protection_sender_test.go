@@ -0,0 +1,179 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type webhookTestServer struct {
+	*httptest.Server
+	mu             sync.Mutex
+	optionsHeaders []http.Header
+	posts          int
+}
+
+func newWebhookTestServer(t *testing.T, options http.HandlerFunc) *webhookTestServer {
+	s := &webhookTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if req.Method == http.MethodOptions {
+			s.optionsHeaders = append(s.optionsHeaders, req.Header.Clone())
+			options(rw, req)
+			return
+		}
+		s.posts++
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookTestServer) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.optionsHeaders), s.posts
+}
+
+func sendTestEvent(p *Protocol) error {
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	return p.Send(context.Background(), binding.ToMessage(&e))
+}
+
+func TestWebhookValidation(t *testing.T) {
+	testCases := map[string]struct {
+		options     http.HandlerFunc
+		wantAllowed bool
+	}{
+		"allowed origin": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", req.Header.Get("WebHook-Request-Origin"))
+			},
+			wantAllowed: true,
+		},
+		"any origin": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", "*")
+				rw.Header().Set("WebHook-Allowed-Rate", "*")
+			},
+			wantAllowed: true,
+		},
+		"other origin": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", "other.example.com")
+			},
+		},
+		"prefix of the origin": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", "sender")
+			},
+		},
+		"no allowed origin": {
+			options: func(rw http.ResponseWriter, req *http.Request) {},
+		},
+		"invalid rate": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", "*")
+				rw.Header().Set("WebHook-Allowed-Rate", "fast")
+			},
+		},
+		"method not allowed": {
+			options: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("WebHook-Allowed-Origin", "*")
+				rw.WriteHeader(http.StatusMethodNotAllowed)
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			server := newWebhookTestServer(t, tc.options)
+			p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookValidation("sender.example.com", 120))
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				err = sendTestEvent(p)
+				if tc.wantAllowed {
+					require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+				} else {
+					require.True(t, errors.Is(err, ErrWebhookNotAllowed), "unexpected result %v", err)
+				}
+			}
+
+			options, posts := server.counts()
+			if tc.wantAllowed {
+				// The permission is cached
+				require.Equal(t, 1, options)
+				require.Equal(t, 2, posts)
+			} else {
+				// The refusals are not cached
+				require.Equal(t, 2, options)
+				require.Equal(t, 0, posts)
+			}
+			require.Equal(t, "sender.example.com", server.optionsHeaders[0].Get("WebHook-Request-Origin"))
+			require.Equal(t, "120", server.optionsHeaders[0].Get("WebHook-Request-Rate"))
+		})
+	}
+}
+
+func TestWebhookValidation_throttle(t *testing.T) {
+	server := newWebhookTestServer(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("WebHook-Allowed-Origin", "*")
+		// One request every 100ms
+		rw.Header().Set("WebHook-Allowed-Rate", "600")
+	})
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookValidation("sender.example.com", 0))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.True(t, protocol.IsACK(sendTestEvent(p)))
+	}
+	require.True(t, time.Since(start) >= 300*time.Millisecond, "the requests were not throttled: %v", time.Since(start))
+
+	_, ok := server.optionsHeaders[0]["Webhook-Request-Rate"]
+	require.False(t, ok)
+}
+
+func TestWebhookValidation_sdkReceiver(t *testing.T) {
+	rate := 60000
+	receiver, err := New()
+	require.NoError(t, err)
+	receiver.WebhookConfig = &WebhookConfig{AllowedRate: &rate, AllowedOrigins: []string{"https://sender.example.com"}}
+	server := newWebhookTestServer(t, receiver.OptionsHandler)
+
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookValidation("https://sender.example.com", 0))
+	require.NoError(t, err)
+	require.True(t, protocol.IsACK(sendTestEvent(p)))
+
+	for _, origin := range []string{"https://other.example.com", "https://sender.example.com.evil"} {
+		p, err = New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookValidation(origin, 0))
+		require.NoError(t, err)
+		require.True(t, errors.Is(sendTestEvent(p), ErrWebhookNotAllowed))
+	}
+}
+
+func TestWithWebhookValidation(t *testing.T) {
+	_, err := New(WithWebhookValidation(" ", 0))
+	require.EqualError(t, err, "http webhook validation option was given an empty origin")
+	_, err = New(WithWebhookValidation("origin", -1))
+	require.EqualError(t, err, "http webhook validation option was given an invalid request rate: -1")
+}
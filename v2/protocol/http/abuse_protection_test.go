@@ -0,0 +1,163 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// newWebhookReceiver serves p, acknowledging the received messages and collecting the reported rejections
+func newWebhookReceiver(t *testing.T, p *Protocol) (*httptest.Server, <-chan error) {
+	rejections := make(chan error, 10)
+	require.NoError(t, WithRejectionObserver(func(_ *http.Request, err error) {
+		rejections <- err
+	})(p))
+
+	server := httptest.NewServer(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+
+	go func() {
+		for {
+			msg, respFn, err := p.Respond(ctx)
+			if err == io.EOF {
+				return
+			}
+			if msg == nil {
+				t.Errorf("unexpected error: %v", err)
+				continue
+			}
+			_ = msg.Finish(nil)
+			_ = respFn(ctx, nil, nil)
+		}
+	}()
+	return server, rejections
+}
+
+func postWebhookRequest(t *testing.T, url string, origin string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader("hello"))
+	require.NoError(t, err)
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "1")
+	req.Header.Set("ce-type", "type")
+	req.Header.Set("ce-source", "source")
+	if origin != "" {
+		req.Header.Set("WebHook-Request-Origin", origin)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestWebhookAllowedOrigins(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+	p.WebhookConfig = &WebhookConfig{AllowedOrigins: []string{"https://allowed.example.com"}}
+	server, rejections := newWebhookReceiver(t, p)
+
+	require.Equal(t, http.StatusOK, postWebhookRequest(t, server.URL, "https://allowed.example.com").StatusCode)
+
+	for _, origin := range []string{"https://other.example.com", ""} {
+		require.Equal(t, http.StatusForbidden, postWebhookRequest(t, server.URL, origin).StatusCode)
+		require.True(t, errors.Is(<-rejections, ErrOriginNotAllowed))
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	config := &WebhookConfig{AllowedOrigins: []string{"https://allowed.example.com", "http://local.example.com:8080", "bare.example.com"}}
+	for origin, want := range map[string]bool{
+		"https://allowed.example.com":          true,
+		"HTTPS://Allowed.Example.com:443":      true,
+		"https://allowed.example.com/":         true,
+		"allowed.example.com":                  true,
+		"http://local.example.com:8080":        true,
+		"https://bare.example.com":             true,
+		"bare.example.com":                     true,
+		"https://allowed.example.com.evil":     false,
+		"https://allowed.example.com:8443":     false,
+		"http://allowed.example.com":           false,
+		"https://allowed.example.com/path":     false,
+		"https://user@allowed.example.com":     false,
+		"http://local.example.com":             false,
+		"https://evil.com/allowed.example.com": false,
+		"":                                     false,
+	} {
+		_, got := matchOrigin(config, origin)
+		require.Equal(t, want, got, origin)
+	}
+
+	origin, ok := matchOrigin(&WebhookConfig{AllowedOrigins: []string{"*"}}, "https://any.example.com")
+	require.True(t, ok)
+	require.Equal(t, "*", origin)
+}
+
+func TestWebhookAllowedRate(t *testing.T) {
+	rate := 60 // one request per second
+	p, err := New()
+	require.NoError(t, err)
+	p.WebhookConfig = &WebhookConfig{AllowedRate: &rate}
+	server, rejections := newWebhookReceiver(t, p)
+
+	require.Equal(t, http.StatusOK, postWebhookRequest(t, server.URL, "https://a.example.com").StatusCode)
+	resp := postWebhookRequest(t, server.URL, "https://a.example.com")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(RetryAfter))
+	require.True(t, errors.Is(<-rejections, ErrRateLimited))
+
+	// The rate is limited per origin
+	require.Equal(t, http.StatusOK, postWebhookRequest(t, server.URL, "https://b.example.com").StatusCode)
+
+	// or per client IP without origin
+	require.Equal(t, http.StatusOK, postWebhookRequest(t, server.URL, "").StatusCode)
+	require.Equal(t, http.StatusTooManyRequests, postWebhookRequest(t, server.URL, "").StatusCode)
+	<-rejections
+}
+
+func TestWebhookAllowedRate_sender(t *testing.T) {
+	rate := 600 // one request every 100ms
+	receiver, err := New()
+	require.NoError(t, err)
+	receiver.OptionsHandlerFn = receiver.OptionsHandler
+	receiver.WebhookConfig = &WebhookConfig{AllowedRate: &rate, AllowedOrigins: []string{"https://sender.example.com"}}
+	server, _ := newWebhookReceiver(t, receiver)
+
+	// A sender running the handshake sends its origin and is throttled to the allowed rate
+	sender, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookValidation("https://sender.example.com", 0))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.True(t, protocol.IsACK(sendTestEvent(sender)))
+	}
+}
+
+func TestRateLimiters_prune(t *testing.T) {
+	var limiters rateLimiters
+	now := time.Now()
+	for i := 0; i < maxRateLimiters; i++ {
+		ok, _ := limiters.allow(string(rune('a'+i%26))+strings.Repeat("x", i/26), 60, now)
+		require.True(t, ok)
+	}
+	require.Len(t, limiters.limiters, maxRateLimiters)
+
+	// After a minute the limiters are full again and they are forgotten
+	ok, _ := limiters.allow("new", 60, now.Add(time.Minute))
+	require.True(t, ok)
+	require.Len(t, limiters.limiters, 1)
+}
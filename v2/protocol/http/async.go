@@ -0,0 +1,208 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// asyncRetryAfter is the delay requested to the senders when the async queue is full
+const asyncRetryAfter = time.Second
+
+// ErrAsyncQueueFull is returned by AsyncQueue.Push when the queue can't accept more events
+var ErrAsyncQueueFull = errors.New("async queue is full")
+
+// errAsyncNotDelivered finishes the message popped from the async queue and not delivered when the receiver stops
+var errAsyncNotDelivered = errors.New("async event popped after the receiver stopped, not delivered")
+
+// AsyncQueue queues the events accepted by a Protocol in async receive mode, see WithAsyncQueue.
+// Implementations backed by a persistent storage keep the events across restarts of the receiver.
+type AsyncQueue interface {
+	// Push adds e to the queue, returning ErrAsyncQueueFull if the queue is full.
+	// The event is acknowledged to the sender once Push returns without error.
+	Push(ctx context.Context, e *event.Event) error
+	// Pop blocks until an event is available or ctx is done. It's invoked by a single goroutine of the Protocol,
+	// from the first Respond until the receiver stops, when ctx is done.
+	// The Finish of the returned message is called with the result of the handler, or with an error when
+	// the receiver stopped before delivering it: a persistent queue removes the event on a successful result,
+	// and can deliver it again otherwise.
+	Pop(ctx context.Context) (binding.Message, error)
+}
+
+// memoryQueue is the bounded in memory AsyncQueue
+type memoryQueue struct {
+	events chan binding.Message
+}
+
+// asyncMessage is an event queued in memory, with the values of the context of its request
+type asyncMessage struct {
+	*binding.EventMessage
+	ctx context.Context
+}
+
+func (m *asyncMessage) Context() context.Context {
+	return m.ctx
+}
+
+func (m *asyncMessage) GetWrappedMessage() binding.Message {
+	return m.EventMessage
+}
+
+var _ binding.MessageWrapper = (*asyncMessage)(nil)
+var _ binding.MessageContext = (*asyncMessage)(nil)
+
+// detachedContext keeps the values of a request context, without its cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// NewMemoryQueue returns an AsyncQueue holding up to size events in memory.
+// The events of the queue are lost when the receiver stops.
+func NewMemoryQueue(size int) AsyncQueue {
+	return &memoryQueue{events: make(chan binding.Message, size)}
+}
+
+func (q *memoryQueue) Push(ctx context.Context, e *event.Event) error {
+	select {
+	case q.events <- &asyncMessage{EventMessage: (*binding.EventMessage)(e), ctx: detachedContext{ctx}}:
+		return nil
+	default:
+		return ErrAsyncQueueFull
+	}
+}
+
+func (q *memoryQueue) Pop(ctx context.Context) (binding.Message, error) {
+	select {
+	case m := <-q.events:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// noAsyncRespFn is the ResponseFn of the messages received in async mode, whose request is already answered
+func noAsyncRespFn(_ context.Context, respMsg binding.Message, _ protocol.Result, _ ...binding.Transformer) error {
+	if respMsg != nil {
+		return respMsg.Finish(nil)
+	}
+	return nil
+}
+
+// serveAsync parses and validates the event of req and pushes it to the async queue, answering 202 Accepted
+// without waiting for the handler. An invalid event is rejected with 400 Bad Request, see WithAsyncValidation.
+func (p *Protocol) serveAsync(rw http.ResponseWriter, req *http.Request, m *Message) {
+	e, err := binding.ToEvent(req.Context(), m)
+	if err == nil {
+		err = p.validateAsync(e)
+	}
+	_ = m.Finish(err)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, binding.ErrUnknownEncoding) {
+			status = http.StatusUnsupportedMediaType
+		} else if errors.Is(err, binding.ErrLimitExceeded) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(rw, fmt.Sprintf("Cannot accept CloudEvent: %s", err), status)
+		p.reportRejection(req, err)
+		return
+	}
+
+	if err := p.asyncQueue.Push(req.Context(), e); err != nil {
+		if errors.Is(err, ErrAsyncQueueFull) {
+			rw.Header().Set(RetryAfter, strconv.Itoa(int(math.Ceil(asyncRetryAfter.Seconds()))))
+			http.Error(rw, "Too many pending events", http.StatusServiceUnavailable)
+		} else {
+			http.Error(rw, fmt.Sprintf("Cannot queue CloudEvent: %s", err), http.StatusInternalServerError)
+		}
+		p.reportRejection(req, err)
+		return
+	}
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// validateAsync validates e before it's queued
+func (p *Protocol) validateAsync(e *event.Event) error {
+	if p.asyncValidate != nil {
+		return p.asyncValidate(e)
+	}
+	return e.Validate()
+}
+
+// asyncMessages returns the channel delivering the events of the async queue. The events of a persistent queue
+// are popped by a single pump, started by the first Respond and running until the receiver stops, see stopAsyncPump.
+func (p *Protocol) asyncMessages() <-chan binding.Message {
+	if q, ok := p.asyncQueue.(*memoryQueue); ok {
+		// Received directly from the queue, so no event is lost when Respond returns
+		return q.events
+	}
+
+	p.asyncMu.Lock()
+	defer p.asyncMu.Unlock()
+	if p.asyncPumped == nil {
+		p.asyncPumped = make(chan binding.Message)
+	}
+	if p.asyncCancel == nil {
+		var ctx context.Context
+		ctx, p.asyncCancel = context.WithCancel(context.Background())
+		go p.pumpAsync(ctx, p.asyncPumped)
+	}
+	return p.asyncPumped
+}
+
+// pumpAsync pops the events of the async queue into ch until ctx is done. The message popped when ctx is done
+// is finished with an error, so the queue can deliver it again.
+func (p *Protocol) pumpAsync(ctx context.Context, ch chan<- binding.Message) {
+	logger := cecontext.LoggerFrom(ctx)
+	for {
+		m, err := p.asyncQueue.Pop(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warnw("cannot pop from the async queue", zap.Error(err))
+			select {
+			case <-time.After(asyncRetryAfter):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case ch <- m:
+		case <-ctx.Done():
+			_ = m.Finish(errAsyncNotDelivered)
+			return
+		}
+	}
+}
+
+// stopAsyncPump stops the pump of the async queue, if started
+func (p *Protocol) stopAsyncPump() {
+	p.asyncMu.Lock()
+	defer p.asyncMu.Unlock()
+	if p.asyncCancel != nil {
+		p.asyncCancel()
+		p.asyncCancel = nil
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestWithAsyncReceive(t *testing.T) {
+	p, err := New(WithAsyncReceive(1), WithRoute("/orders/{id}", nil))
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	// Accepted without waiting for the handler
+	resp, err := postTestEvent(http.DefaultClient, server.URL+"/orders/1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp, err = postTestEvent(http.DefaultClient, server.URL+"/orders/2")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(RetryAfter))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	msg, respFn, err := p.Respond(ctx)
+	require.NoError(t, err)
+	e, err := binding.ToEvent(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, "1", e.ID())
+	msgCtx := msg.(binding.MessageContext).Context()
+	require.Equal(t, map[string]string{"id": "1"}, PathParamsFrom(msgCtx))
+	require.NoError(t, msgCtx.Err())
+	require.NoError(t, msg.Finish(nil))
+	require.NoError(t, respFn(ctx, nil, nil))
+
+	// The queue is consumed again
+	resp, err = postTestEvent(http.DefaultClient, server.URL+"/orders/4")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	msg, _, err = p.Respond(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"id": "4"}, PathParamsFrom(msg.(binding.MessageContext).Context()))
+}
+
+func TestWithAsyncReceive_validation(t *testing.T) {
+	postInvalidEvent := func(url string) int {
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("ce-specversion", "1.0")
+		req.Header.Set("ce-id", "1")
+		req.Header.Set("ce-type", "type")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The event misses its source
+	p, err := New(WithAsyncReceive(1))
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	defer server.Close()
+	require.Equal(t, http.StatusBadRequest, postInvalidEvent(server.URL))
+
+	lenient, err := New(WithAsyncReceive(1), WithAsyncValidation(func(e *event.Event) error { return nil }))
+	require.NoError(t, err)
+	lenientServer := httptest.NewServer(lenient)
+	defer lenientServer.Close()
+	require.Equal(t, http.StatusAccepted, postInvalidEvent(lenientServer.URL))
+
+	msg, _, err := lenient.Respond(context.Background())
+	require.NoError(t, err)
+	e, err := binding.ToEvent(context.Background(), msg)
+	require.NoError(t, err)
+	require.Equal(t, "1", e.ID())
+}
+
+// testQueue is an AsyncQueue reporting the results of the handler
+type testQueue struct {
+	events  chan *event.Event
+	pushErr error
+	results chan error
+	// pops counts the calls of Pop
+	pops int32
+}
+
+func (q *testQueue) Push(_ context.Context, e *event.Event) error {
+	if q.pushErr != nil {
+		return q.pushErr
+	}
+	q.events <- e
+	return nil
+}
+
+func (q *testQueue) Pop(ctx context.Context) (binding.Message, error) {
+	atomic.AddInt32(&q.pops, 1)
+	select {
+	case e := <-q.events:
+		return binding.WithFinish(binding.ToMessage(e), func(err error) { q.results <- err }), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestWithAsyncQueue(t *testing.T) {
+	queue := &testQueue{events: make(chan *event.Event, 1), results: make(chan error, 1)}
+	p, err := New(WithAsyncQueue(queue))
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	resp, err := postTestEvent(http.DefaultClient, server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	msg, _, err := p.Respond(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, msg.Finish(nil))
+	require.NoError(t, <-queue.results)
+
+	queue.pushErr = ErrAsyncQueueFull
+	resp, err = postTestEvent(http.DefaultClient, server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get(RetryAfter))
+
+	queue.pushErr = errors.New("disk full")
+	resp, err = postTestEvent(http.DefaultClient, server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+// lateQueue pops the events without watching the context, like a queue polling a storage
+type lateQueue struct {
+	testQueue
+}
+
+func (q *lateQueue) Pop(context.Context) (binding.Message, error) {
+	return q.testQueue.Pop(context.Background())
+}
+
+func TestWithAsyncQueue_notDelivered(t *testing.T) {
+	queue := &lateQueue{testQueue{events: make(chan *event.Event, 1), results: make(chan error, 1)}}
+	p, err := New(WithAsyncQueue(queue))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = p.Respond(ctx)
+	require.Equal(t, io.EOF, err)
+
+	// Popped without a waiting Respond, the event is held by the pump, and given back to the queue
+	// when the receiver stops
+	e := test.MinEvent()
+	queue.events <- &e
+	require.Eventually(t, func() bool { return len(queue.events) == 0 }, time.Second, time.Millisecond)
+	p.stopAsyncPump()
+	require.Equal(t, errAsyncNotDelivered, <-queue.results)
+}
+
+func TestWithAsyncQueue_singlePump(t *testing.T) {
+	queue := &testQueue{events: make(chan *event.Event, 1), results: make(chan error, 1)}
+	p, err := New(WithAsyncQueue(queue))
+	require.NoError(t, err)
+	defer p.stopAsyncPump()
+
+	// The calls of Respond share the same pump
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		_, _, err = p.Respond(ctx)
+		cancel()
+		require.Equal(t, io.EOF, err)
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&queue.pops) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, int32(1), atomic.LoadInt32(&queue.pops))
+
+	e := test.MinEvent()
+	queue.events <- &e
+	msg, _, err := p.Respond(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, msg.Finish(nil))
+	require.NoError(t, <-queue.results)
+}
+
+func TestWithAsyncReceive_invalid(t *testing.T) {
+	_, err := New(WithAsyncReceive(0))
+	require.EqualError(t, err, "http async receive option was given an invalid queue size: 0")
+	_, err = New(WithAsyncQueue(nil))
+	require.EqualError(t, err, "http async queue option was given a nil queue")
+}
@@ -0,0 +1,167 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authorization is the header carrying the bearer tokens
+const Authorization = "Authorization"
+
+// TokenSource provides the bearer tokens sent by the Protocol with WithTokenSource.
+type TokenSource interface {
+	// Token returns a valid access token, fetching a new one if needed.
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenInvalidator is implemented by the TokenSources able to discard a token, so that the next call to Token
+// fetches a new one. When the receiver replies 401 Unauthorized, the Protocol invalidates the token and sends the
+// request again, once, with a new token.
+type TokenInvalidator interface {
+	// Invalidate discards token if it's the current token of the source.
+	Invalidate(token string)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// StaticTokenSource returns a TokenSource always returning token.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+// tokenExpiryDelta is the delay before the expiry of a token when it's refreshed, so that it doesn't expire in flight
+const tokenExpiryDelta = 10 * time.Second
+
+// ClientCredentialsTokenSource fetches and caches the access tokens of the OAuth2 client credentials flow:
+// https://tools.ietf.org/html/rfc6749#section-4.4
+// The tokens are refreshed shortly before their expiry.
+type ClientCredentialsTokenSource struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Client is used to request the tokens, http.DefaultClient if nil.
+	Client *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewClientCredentialsTokenSource returns a TokenSource requesting the tokens from tokenURL with the OAuth2 client credentials flow.
+func NewClientCredentialsTokenSource(tokenURL, clientID, clientSecret string, scopes ...string) *ClientCredentialsTokenSource {
+	return &ClientCredentialsTokenSource{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	}
+}
+
+// Token implements TokenSource.
+func (s *ClientCredentialsTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(s.expiry)) {
+		return s.token, nil
+	}
+
+	token, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiry = expiry
+	return token, nil
+}
+
+// Invalidate implements TokenInvalidator.
+func (s *ClientCredentialsTokenSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = ""
+		s.expiry = time.Time{}
+	}
+}
+
+func (s *ClientCredentialsTokenSource) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.Scopes, " "))
+	}
+	req, err := http.NewRequest(http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.ClientID), url.QueryEscape(s.ClientSecret))
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot fetch the oauth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot fetch the oauth2 token: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", time.Time{}, fmt.Errorf("cannot fetch the oauth2 token: status code %d: %s", resp.StatusCode, body)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", time.Time{}, fmt.Errorf("cannot parse the oauth2 token: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("cannot parse the oauth2 token: no access_token")
+	}
+	if tokenResp.TokenType != "" && !strings.EqualFold(tokenResp.TokenType, "bearer") {
+		return "", time.Time{}, fmt.Errorf("unsupported oauth2 token type %q", tokenResp.TokenType)
+	}
+	var expiry time.Time
+	if tokenResp.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	return tokenResp.AccessToken, expiry, nil
+}
+
+// authorize sets the bearer token of req, returning the token sent
+func (p *Protocol) authorize(req *http.Request) (string, error) {
+	token, err := p.tokenSource.Token(req.Context())
+	if err != nil {
+		return "", err
+	}
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set(Authorization, "Bearer "+token)
+	return token, nil
+}
@@ -0,0 +1,481 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // register the hashes of the signature algorithms
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidBearerToken is reported when a request is rejected because its bearer token is missing or invalid.
+var ErrInvalidBearerToken = errors.New("invalid bearer token")
+
+// Claims are the claims of the JWT bearer token of a request.
+type Claims map[string]interface{}
+
+type authKey int
+
+const (
+	claimsKey authKey = iota
+)
+
+// ClaimsFrom returns the claims of the bearer token of the request delivering the event,
+// when the Protocol validates the tokens with WithBearerTokenValidation. Returns nil otherwise.
+func ClaimsFrom(ctx context.Context) Claims {
+	if claims, ok := ctx.Value(claimsKey).(Claims); ok {
+		return claims
+	}
+	return nil
+}
+
+func withClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// KeySet provides the public keys verifying the signatures of the JWT bearer tokens.
+type KeySet interface {
+	// Key returns the key identified by kid, the "kid" header of the token, which is empty if the token doesn't
+	// identify its key.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type staticKeySet map[string]crypto.PublicKey
+
+func (s staticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(s) == 1 {
+		for _, key := range s {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// NewStaticKeySet returns a KeySet of the given *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey keys,
+// indexed by their id. If the set holds a single key, it's also used for the tokens without key id.
+func NewStaticKeySet(keys map[string]crypto.PublicKey) KeySet {
+	return staticKeySet(keys)
+}
+
+// ParseKeySet returns a KeySet of the keys of a JSON Web Key Set document: https://tools.ietf.org/html/rfc7517#section-5
+func ParseKeySet(jwks []byte) (KeySet, error) {
+	keys, err := parseJWKS(jwks)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+const (
+	// remoteKeySetMaxAge is the delay before the keys of a RemoteKeySet are fetched again
+	remoteKeySetMaxAge = time.Hour
+	// remoteKeySetMinRefresh is the minimal delay between two fetches triggered by unknown keys
+	remoteKeySetMinRefresh = time.Minute
+)
+
+// RemoteKeySet fetches the keys from a JSON Web Key Set URL and caches them. They're fetched again after an hour,
+// or when a token is signed by an unknown key, at most once a minute, including after a failed fetch.
+type RemoteKeySet struct {
+	URL string
+	// Client is used to fetch the keys, http.DefaultClient if nil.
+	Client *http.Client
+
+	mu      sync.Mutex
+	keys    staticKeySet
+	fetched time.Time
+	// attempted is the time of the last fetch, successful or not, and err its error
+	attempted time.Time
+	err       error
+	// inflight is the ongoing fetch, shared by the concurrent callers
+	inflight chan struct{}
+}
+
+// NewRemoteKeySet returns a KeySet fetching the keys from the JSON Web Key Set at url.
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{URL: url}
+}
+
+// Key implements KeySet.
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	keys, fetched := s.keys, s.fetched
+	s.mu.Unlock()
+
+	if keys == nil || time.Since(fetched) > remoteKeySetMaxAge {
+		var err error
+		if keys, err = s.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	key, err := keys.Key(ctx, kid)
+	if err != nil {
+		// The keys may have been rotated
+		if keys, err = s.refresh(ctx); err != nil {
+			return nil, err
+		}
+		return keys.Key(ctx, kid)
+	}
+	return key, err
+}
+
+// refresh fetches the keys, unless the last fetch was attempted less than a minute ago, and returns the cached keys
+// with the error of the last fetch. The fetch happens without holding the lock, once for the concurrent callers.
+func (s *RemoteKeySet) refresh(ctx context.Context) (staticKeySet, error) {
+	s.mu.Lock()
+	if done := s.inflight; done != nil {
+		s.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.keys, s.err
+	}
+	if !s.attempted.IsZero() && time.Since(s.attempted) < remoteKeySetMinRefresh {
+		defer s.mu.Unlock()
+		return s.keys, s.err
+	}
+	done := make(chan struct{})
+	s.inflight = done
+	previous := s.attempted
+	s.attempted = time.Now()
+	s.mu.Unlock()
+
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case err == nil:
+		s.keys = keys
+		s.fetched = time.Now()
+	case ctx.Err() != nil:
+		// Canceled by the caller, not a failure of the key set
+		s.attempted = previous
+	}
+	s.err = err
+	s.inflight = nil
+	close(done)
+	return s.keys, err
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (staticKeySet, error) {
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch the key set: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch the key set: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot fetch the key set: status code %d", resp.StatusCode)
+	}
+	return parseJWKS(body)
+}
+
+// jsonWebKey holds the members of the RSA, EC and OKP keys: https://tools.ietf.org/html/rfc7518#section-6
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the signature keys of a JSON Web Key Set, ignoring the encryption keys and the unsupported key types
+func parseJWKS(jwks []byte) (staticKeySet, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(jwks, &set); err != nil {
+		return nil, fmt.Errorf("cannot parse the key set: %w", err)
+	}
+	keys := make(staticKeySet, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey returns the key, or nil if its type isn't supported
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// jwtLeeway is the clock skew tolerated when checking the expiry of the tokens
+const jwtLeeway = time.Minute
+
+// tokenValidator validates the JWT bearer tokens of the inbound requests
+type tokenValidator struct {
+	keys     KeySet
+	issuer   string
+	audience string
+}
+
+// validateRequest returns the claims of the bearer token of req, or an error wrapping ErrInvalidBearerToken
+func (v *tokenValidator) validateRequest(req *http.Request) (Claims, error) {
+	authorization := req.Header.Get(Authorization)
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return nil, fmt.Errorf("%w: missing bearer token", ErrInvalidBearerToken)
+	}
+	return v.validate(req.Context(), strings.TrimSpace(authorization[7:]), time.Now())
+}
+
+// validate verifies the signature of token and its registered claims: https://tools.ietf.org/html/rfc7519#section-4.1
+func (v *tokenValidator) validate(ctx context.Context, token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidBearerToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header: %v", ErrInvalidBearerToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature: %v", ErrInvalidBearerToken, err)
+	}
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBearerToken, err)
+	}
+	if err := verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBearerToken, err)
+	}
+
+	var claims Claims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims: %v", ErrInvalidBearerToken, err)
+	}
+	if err := v.validateClaims(claims, now); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBearerToken, err)
+	}
+	return claims, nil
+}
+
+func (v *tokenValidator) validateClaims(claims Claims, now time.Time) error {
+	if exp, ok, err := numericDateClaim(claims, "exp"); err != nil {
+		return err
+	} else if ok && now.Add(-jwtLeeway).After(exp) {
+		return errors.New("token expired")
+	}
+	if nbf, ok, err := numericDateClaim(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(jwtLeeway).Before(nbf) {
+		return errors.New("token not valid yet")
+	}
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+	if v.audience != "" {
+		var audiences []interface{}
+		switch aud := claims["aud"].(type) {
+		case string:
+			audiences = []interface{}{aud}
+		case []interface{}:
+			audiences = aud
+		}
+		for _, aud := range audiences {
+			if aud == v.audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("unexpected audience %v", claims["aud"])
+	}
+	return nil
+}
+
+func numericDateClaim(claims Claims, name string) (time.Time, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	n, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	seconds, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	return time.Unix(int64(seconds), 0), true, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// verifyJWTSignature verifies the signature of the asymmetric algorithms: https://tools.ietf.org/html/rfc7518#section-3.1
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(edKey, signed, signature) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	h := hash.New()
+	_, _ = h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[0] {
+	case 'R':
+		if rsaKey, ok := key.(*rsa.PublicKey); ok && rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature) == nil {
+			return nil
+		}
+	case 'P':
+		if rsaKey, ok := key.(*rsa.PublicKey); ok &&
+			rsa.VerifyPSS(rsaKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil {
+			return nil
+		}
+	case 'E':
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		// The curve is bound to the algorithm
+		if !ok || ecKey.Curve.Params().BitSize != map[string]int{"ES256": 256, "ES384": 384, "ES512": 521}[alg] {
+			break
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			break
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if ecdsa.Verify(ecKey, digest, r, s) {
+			return nil
+		}
+	}
+	return errors.New("invalid signature")
+}
@@ -0,0 +1,357 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type testJWTKeys struct {
+	rsa     *rsa.PrivateKey
+	ecdsa   *ecdsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+}
+
+func newTestJWTKeys(t *testing.T) testJWTKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return testJWTKeys{rsa: rsaKey, ecdsa: ecdsaKey, ed25519: ed25519Key}
+}
+
+func (k testJWTKeys) keySet() KeySet {
+	return NewStaticKeySet(map[string]crypto.PublicKey{
+		"rsa":     &k.rsa.PublicKey,
+		"ecdsa":   &k.ecdsa.PublicKey,
+		"ed25519": k.ed25519.Public(),
+	})
+}
+
+// jwks returns the JSON Web Key Set of the public keys
+func (k testJWTKeys) jwks() []byte {
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ecdsa", "crv": "P-256", "x": b64(k.ecdsa.X.Bytes()), "y": b64(k.ecdsa.Y.Bytes())},
+		{"kty": "OKP", "kid": "ed25519", "crv": "Ed25519", "x": b64(k.ed25519.Public().(ed25519.PublicKey))},
+		{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "invalid"},
+	}})
+	return jwks
+}
+
+func signTestJWT(t *testing.T, alg string, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := b64(header) + "." + b64(payload)
+
+	var signature []byte
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signed))
+	case *ecdsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		_, _ = digest.Write([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		require.NoError(t, err)
+		signature = make([]byte, 64)
+		rBytes, sBytes := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rBytes):32], rBytes)
+		copy(signature[64-len(sBytes):], sBytes)
+	case *rsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		_, _ = digest.Write([]byte(signed))
+		if strings.HasPrefix(alg, "PS") {
+			signature, err = rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest.Sum(nil), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+		}
+		require.NoError(t, err)
+	}
+	return signed + "." + b64(signature)
+}
+
+func TestTokenValidator(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	now := time.Now()
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"iss": "issuer", "aud": "audience", "sub": "sender", "exp": now.Add(time.Hour).Unix()}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	testCases := map[string]struct {
+		token   string
+		wantErr string
+	}{
+		"RS256": {
+			token: signTestJWT(t, "RS256", "rsa", keys.rsa, claims(nil)),
+		},
+		"PS256": {
+			token: signTestJWT(t, "PS256", "rsa", keys.rsa, claims(nil)),
+		},
+		"ES256": {
+			token: signTestJWT(t, "ES256", "ecdsa", keys.ecdsa, claims(nil)),
+		},
+		"EdDSA": {
+			token: signTestJWT(t, "EdDSA", "ed25519", keys.ed25519, claims(nil)),
+		},
+		"audience list": {
+			token: signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"aud": []string{"other", "audience"}})),
+		},
+		"expired within leeway": {
+			token: signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})),
+		},
+		"expired": {
+			token:   signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+			wantErr: "invalid bearer token: token expired",
+		},
+		"not valid yet": {
+			token:   signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+			wantErr: "invalid bearer token: token not valid yet",
+		},
+		"other issuer": {
+			token:   signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"iss": "other"})),
+			wantErr: `invalid bearer token: unexpected issuer "other"`,
+		},
+		"no audience": {
+			token:   signTestJWT(t, "RS256", "rsa", keys.rsa, claims(map[string]interface{}{"aud": nil})),
+			wantErr: "invalid bearer token: unexpected audience <nil>",
+		},
+		"unknown key": {
+			token:   signTestJWT(t, "RS256", "other", keys.rsa, claims(nil)),
+			wantErr: `invalid bearer token: unknown key "other"`,
+		},
+		"algorithm of another key type": {
+			token:   signTestJWT(t, "ES256", "rsa", keys.ecdsa, claims(nil)),
+			wantErr: "invalid bearer token: invalid signature",
+		},
+		"none algorithm": {
+			token:   signTestJWT(t, "none", "rsa", keys.ed25519, claims(nil)),
+			wantErr: `invalid bearer token: unsupported algorithm "none"`,
+		},
+		"tampered claims": {
+			token: func() string {
+				parts := strings.Split(signTestJWT(t, "RS256", "rsa", keys.rsa, claims(nil)), ".")
+				payload, _ := json.Marshal(claims(map[string]interface{}{"sub": "admin"}))
+				return parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+			}(),
+			wantErr: "invalid bearer token: invalid signature",
+		},
+		"malformed": {
+			token:   "token",
+			wantErr: "invalid bearer token: malformed token",
+		},
+	}
+	validator := &tokenValidator{keys: keys.keySet(), issuer: "issuer", audience: "audience"}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			claims, err := validator.validate(context.Background(), tc.token, now)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				require.True(t, errors.Is(err, ErrInvalidBearerToken))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "sender", claims["sub"])
+		})
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	keySet, err := ParseKeySet(keys.jwks())
+	require.NoError(t, err)
+	// The symmetric and encryption keys are ignored
+	require.Len(t, keySet, 3)
+
+	validator := &tokenValidator{keys: keySet}
+	for alg, signer := range map[string]struct {
+		kid string
+		key crypto.Signer
+	}{"RS256": {"rsa", keys.rsa}, "ES256": {"ecdsa", keys.ecdsa}, "EdDSA": {"ed25519", keys.ed25519}} {
+		_, err := validator.validate(context.Background(), signTestJWT(t, alg, signer.kid, signer.key, map[string]interface{}{}), time.Now())
+		require.NoError(t, err, alg)
+	}
+
+	_, err = ParseKeySet([]byte(`{"keys": [{"kty": "EC", "kid": "invalid", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`))
+	require.EqualError(t, err, `cannot parse the key "invalid": invalid point`)
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	var mu sync.Mutex
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		_, _ = rw.Write(keys.jwks())
+	}))
+	t.Cleanup(server.Close)
+	fetched := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches
+	}
+
+	keySet := NewRemoteKeySet(server.URL)
+	key, err := keySet.Key(context.Background(), "rsa")
+	require.NoError(t, err)
+	require.Equal(t, &keys.rsa.PublicKey, key)
+	_, err = keySet.Key(context.Background(), "ecdsa")
+	require.NoError(t, err)
+
+	// The unknown keys are fetched again at most once a minute
+	_, err = keySet.Key(context.Background(), "unknown")
+	require.EqualError(t, err, `unknown key "unknown"`)
+	require.Equal(t, 1, fetched())
+
+	keySet.attempted = keySet.attempted.Add(-2 * remoteKeySetMinRefresh)
+	_, err = keySet.Key(context.Background(), "unknown")
+	require.EqualError(t, err, `unknown key "unknown"`)
+	require.Equal(t, 2, fetched())
+}
+
+func TestRemoteKeySet_failedFetch(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	var mu sync.Mutex
+	fetches := 0
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		fetches++
+		first := fetches == 1
+		mu.Unlock()
+		if first {
+			<-release
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = rw.Write(keys.jwks())
+	}))
+	t.Cleanup(server.Close)
+	fetched := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches
+	}
+
+	// The concurrent callers share the same fetch
+	keySet := NewRemoteKeySet(server.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keySet.Key(context.Background(), "rsa")
+			require.EqualError(t, err, "cannot fetch the key set: status code 500")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, 1, fetched())
+
+	// A failed fetch is attempted again at most once a minute
+	_, err := keySet.Key(context.Background(), "rsa")
+	require.EqualError(t, err, "cannot fetch the key set: status code 500")
+	require.Equal(t, 1, fetched())
+
+	keySet.attempted = keySet.attempted.Add(-2 * remoteKeySetMinRefresh)
+	key, err := keySet.Key(context.Background(), "rsa")
+	require.NoError(t, err)
+	require.Equal(t, &keys.rsa.PublicKey, key)
+	require.Equal(t, 2, fetched())
+}
+
+func TestWithBearerTokenValidation(t *testing.T) {
+	keys := newTestJWTKeys(t)
+	rejections := make(chan error, 1)
+	p, err := New(WithBearerTokenValidation(keys.keySet(), "issuer", "audience"), WithRejectionObserver(func(_ *http.Request, err error) {
+		rejections <- err
+	}))
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+
+	received := make(chan Claims, 1)
+	go func() {
+		for {
+			msg, respFn, err := p.Respond(ctx)
+			if err == io.EOF {
+				return
+			}
+			received <- ClaimsFrom(msg.(*Message).Context())
+			_ = msg.Finish(nil)
+			_ = respFn(ctx, nil, nil)
+		}
+	}()
+
+	token := signTestJWT(t, "ES256", "ecdsa", keys.ecdsa, map[string]interface{}{"iss": "issuer", "aud": "audience", "sub": "sender"})
+	sender, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithBearerToken(token))
+	require.NoError(t, err)
+	require.True(t, protocol.IsACK(sendTestEvent(sender)))
+	require.Equal(t, "sender", (<-received)["sub"])
+
+	for _, authorization := range []string{"", "Basic dXNlcjpwYXNzd29yZA==", "Bearer " + token[:len(token)-4]} {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("hello"))
+		require.NoError(t, err)
+		req.Header.Set("ce-specversion", "1.0")
+		req.Header.Set("ce-id", "1")
+		req.Header.Set("ce-type", "type")
+		req.Header.Set("ce-source", "source")
+		if authorization != "" {
+			req.Header.Set(Authorization, authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		require.Equal(t, `Bearer error="invalid_token"`, resp.Header.Get("WWW-Authenticate"))
+		require.True(t, errors.Is(<-rejections, ErrInvalidBearerToken))
+	}
+
+	_, err = New(WithBearerTokenValidation(nil, "", ""))
+	require.EqualError(t, err, "http bearer token validation option was given a nil key set")
+}
@@ -0,0 +1,159 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// newTokenServer serves the oauth2 token endpoint, issuing the tokens "token-1", "token-2"... valid for expiresIn seconds
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id, secret, _ := req.BasicAuth()
+		if req.Method != http.MethodPost || id != "client" || secret != "secret" ||
+			req.PostFormValue("grant_type") != "client_credentials" || req.PostFormValue("scope") != "events.write events.read" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		issued++
+		token := fmt.Sprintf("token-%d", issued)
+		mu.Unlock()
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"access_token": token, "token_type": "Bearer", "expires_in": expiresIn})
+	}))
+	t.Cleanup(server.Close)
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return issued
+	}
+}
+
+// newAuthorizedServer accepts the requests with the bearer tokens accepted by authorized
+func newAuthorizedServer(t *testing.T, authorized func(token string) bool) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		token := req.Header.Get(Authorization)
+		mu.Lock()
+		tokens = append(tokens, token)
+		mu.Unlock()
+		if !authorized(token) {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return tokens
+	}
+}
+
+func TestWithBearerToken(t *testing.T) {
+	server, tokens := newAuthorizedServer(t, func(token string) bool { return token == "Bearer static" })
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithBearerToken("static"))
+	require.NoError(t, err)
+
+	require.True(t, protocol.IsACK(sendTestEvent(p)))
+	require.Equal(t, []string{"Bearer static"}, tokens())
+
+	_, err = New(WithBearerToken(""))
+	require.EqualError(t, err, "http bearer token option was given an empty token")
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	server, tokens := newAuthorizedServer(t, func(token string) bool { return true })
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}),
+		WithOAuth2ClientCredentials(tokenServer.URL, "client", "secret", "events.write", "events.read"))
+	require.NoError(t, err)
+
+	// The token is cached
+	require.True(t, protocol.IsACK(sendTestEvent(p)))
+	require.True(t, protocol.IsACK(sendTestEvent(p)))
+	require.Equal(t, 1, issued())
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, tokens())
+}
+
+func TestOAuth2ClientCredentials_refreshBeforeExpiry(t *testing.T) {
+	// The tokens expire within tokenExpiryDelta so they're never reused
+	tokenServer, issued := newTokenServer(t, 5)
+	ts := NewClientCredentialsTokenSource(tokenServer.URL, "client", "secret", "events.write", "events.read")
+
+	token, err := ts.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-1", token)
+	token, err = ts.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "token-2", token)
+	require.Equal(t, 2, issued())
+}
+
+func TestOAuth2ClientCredentials_unauthorized(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+	// The first token was revoked
+	server, tokens := newAuthorizedServer(t, func(token string) bool { return token != "Bearer token-1" })
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}),
+		WithOAuth2ClientCredentials(tokenServer.URL, "client", "secret", "events.write", "events.read"))
+	require.NoError(t, err)
+
+	require.True(t, protocol.IsACK(sendTestEvent(p)))
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, tokens())
+	require.Equal(t, 2, issued())
+
+	// The token is refreshed once per attempt
+	server, tokens = newAuthorizedServer(t, func(token string) bool { return false })
+	p, err = New(WithTarget(server.URL), WithClient(http.Client{}),
+		WithOAuth2ClientCredentials(tokenServer.URL, "client", "secret", "events.write", "events.read"),
+		WithIsRetriableFunc(func(sc int) bool { return sc == http.StatusUnauthorized }))
+	require.NoError(t, err)
+
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	ctx := cecontext.WithRetriesConstantBackoff(context.Background(), time.Millisecond, 1)
+	result := p.Send(ctx, binding.ToMessage(&e))
+	var retriesResult *RetriesResult
+	require.True(t, errors.As(result, &retriesResult), "unexpected result %v", result)
+	require.Equal(t, 1, retriesResult.Retries)
+	var httpResult *Result
+	require.True(t, errors.As(retriesResult.Result, &httpResult))
+	require.Equal(t, http.StatusUnauthorized, httpResult.StatusCode)
+	require.Equal(t, []string{"Bearer token-3", "Bearer token-4", "Bearer token-4", "Bearer token-5"}, tokens())
+	require.Equal(t, 5, issued())
+}
+
+func TestStaticTokenSource_unauthorized(t *testing.T) {
+	server, tokens := newAuthorizedServer(t, func(token string) bool { return false })
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithTokenSource(StaticTokenSource("static")))
+	require.NoError(t, err)
+
+	// A static token can't be refreshed, the request isn't sent again
+	require.False(t, protocol.IsACK(sendTestEvent(p)))
+	require.Len(t, tokens(), 1)
+}
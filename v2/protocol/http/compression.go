@@ -0,0 +1,170 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	ContentEncoding = "Content-Encoding"
+	AcceptEncoding  = "Accept-Encoding"
+
+	// EncodingGzip is the gzip content coding
+	EncodingGzip = "gzip"
+	// EncodingZstd is the Zstandard content coding
+	EncodingZstd = "zstd"
+)
+
+// acceptEncoding is the value of the Accept-Encoding header advertised by the receiver
+// when decompression is enabled
+const acceptEncoding = EncodingGzip + ", " + EncodingZstd
+
+// compressBody compresses the body of req using encoding if its length is at least threshold,
+// setting the Content-Encoding header. A body which can be read several times through req.GetBody
+// is compressed in memory, so the request can be retried; a streamed body is compressed while it's sent.
+func compressBody(req *http.Request, encoding string, threshold int64) error {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get(ContentEncoding) != "" {
+		return nil
+	}
+	if req.GetBody == nil {
+		return compressStream(req, encoding, threshold)
+	}
+	if req.ContentLength < threshold {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := newCompressor(&buf, encoding)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	setContentEncoding(req, encoding)
+	return (*httpRequestWriter)(req).setBody(&buf)
+}
+
+// compressStream compresses a body which can be read only once through a pipe, without buffering it.
+// Only the first threshold bytes are read ahead, to send the shorter bodies as they are.
+// The request can't be retried.
+func compressStream(req *http.Request, encoding string, threshold int64) error {
+	body := req.Body
+	head, err := ioutil.ReadAll(io.LimitReader(body, threshold))
+	if err != nil {
+		_ = body.Close()
+		return err
+	}
+	if int64(len(head)) < threshold {
+		_ = body.Close()
+		return (*httpRequestWriter)(req).setBody(bytes.NewReader(head))
+	}
+
+	pr, pw := io.Pipe()
+	w, err := newCompressor(pw, encoding)
+	if err != nil {
+		_ = body.Close()
+		return err
+	}
+	go func() {
+		// The transport closes pr once the request is sent, or failed, which stops the copy
+		_, err := io.Copy(w, io.MultiReader(bytes.NewReader(head), body))
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		_ = body.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	setContentEncoding(req, encoding)
+	req.Body = pr
+	req.ContentLength = 0
+	req.GetBody = nil
+	return nil
+}
+
+func setContentEncoding(req *http.Request, encoding string) {
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set(ContentEncoding, encoding)
+}
+
+// decompressBody returns a reader of the body decompressed according to the Content-Encoding of header,
+// removing the Content-Encoding and Content-Length headers, which don't apply anymore to the returned reader.
+func decompressBody(header http.Header, body io.ReadCloser) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(header.Get(ContentEncoding)))
+	if encoding == "" || encoding == "identity" {
+		return body, nil
+	}
+
+	var r io.ReadCloser
+	switch encoding {
+	case EncodingGzip, "x-gzip":
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		r = gr
+	case EncodingZstd:
+		zr, err := zstd.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		r = zr.IOReadCloser()
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	header.Del(ContentEncoding)
+	header.Del(ContentLength)
+	return &decompressingReader{ReadCloser: r, body: body}, nil
+}
+
+// decompressingReader closes both the decompressor and the underlying body
+type decompressingReader struct {
+	io.ReadCloser
+	body io.ReadCloser
+}
+
+func (d *decompressingReader) Close() error {
+	_ = d.ReadCloser.Close()
+	return d.body.Close()
+}
+
+func newCompressor(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w), nil
+	case EncodingZstd:
+		return zstd.NewWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+}
+
+func isSupportedEncoding(encoding string) bool {
+	return encoding == EncodingGzip || encoding == EncodingZstd
+}
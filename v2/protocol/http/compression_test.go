@@ -0,0 +1,178 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+func compressionTestEvent(size int) event.Event {
+	e := test.FullEvent()
+	e.Context = e.Context.Clone()
+	for k, v := range e.Extensions() {
+		s, _ := types.Format(v)
+		_ = e.Context.SetExtension(k, s)
+	}
+	_ = e.SetData(event.ApplicationJSON, map[string]string{"payload": strings.Repeat("a", size)})
+	return e
+}
+
+func TestCompression(t *testing.T) {
+	for _, encoding := range []string{EncodingGzip, EncodingZstd} {
+		for _, preferred := range []binding.Encoding{binding.EncodingBinary, binding.EncodingStructured} {
+			for _, size := range []int{10, 10000} {
+				t.Run(fmt.Sprintf("%s/%s/%d", encoding, preferred, size), func(t *testing.T) {
+					receiver, err := New(WithDecompression())
+					require.NoError(t, err)
+					server := httptest.NewServer(receiver)
+					defer server.Close()
+
+					var sentEncoding string
+					var sentLength int64
+					sender, err := New(WithTarget(server.URL), WithCompression(encoding, 1024), WithClient(http.Client{
+						Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+							sentEncoding = req.Header.Get(ContentEncoding)
+							sentLength = req.ContentLength
+							return http.DefaultTransport.RoundTrip(req)
+						}),
+					}))
+					require.NoError(t, err)
+
+					in := compressionTestEvent(size)
+					go func() {
+						_ = sender.Send(binding.WithPreferredEventEncoding(context.Background(), preferred), binding.ToMessage(&in))
+					}()
+
+					msg, fn, err := receiver.Respond(context.Background())
+					require.NoError(t, err)
+					out, err := binding.ToEvent(context.Background(), msg)
+					require.NoError(t, err)
+					require.NoError(t, fn(context.Background(), nil, nil))
+					test.AssertEventEquals(t, in, *out)
+
+					if size < 1024 {
+						require.Empty(t, sentEncoding)
+					} else {
+						require.Equal(t, encoding, sentEncoding)
+						require.Less(t, sentLength, int64(size))
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestCompression_Retry(t *testing.T) {
+	receiver, err := New(WithDecompression())
+	require.NoError(t, err)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	sender, err := New(WithTarget(server.URL), WithCompression(EncodingGzip, 0))
+	require.NoError(t, err)
+
+	in := compressionTestEvent(2000)
+	ctx := cecontext.WithRetriesConstantBackoff(context.Background(), time.Millisecond, 3)
+	done := make(chan protocol.Result)
+	go func() {
+		done <- sender.Send(ctx, binding.ToMessage(&in))
+	}()
+
+	for i, status := range []int{http.StatusServiceUnavailable, http.StatusOK} {
+		msg, fn, err := receiver.Respond(context.Background())
+		require.NoError(t, err)
+		out, err := binding.ToEvent(context.Background(), msg)
+		require.NoError(t, err, "attempt %d", i)
+		test.AssertEventEquals(t, in, *out)
+		require.NoError(t, fn(context.Background(), nil, NewResult(status, "")))
+	}
+	require.True(t, protocol.IsACK(<-done))
+}
+
+func TestCompression_Stream(t *testing.T) {
+	for _, size := range []int{10, 10000} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			var attempts int
+			var sentEncoding string
+			var sentBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				attempts++
+				sentEncoding = req.Header.Get(ContentEncoding)
+				body, err := decompressBody(req.Header, req.Body)
+				require.NoError(t, err)
+				sentBody, err = ioutil.ReadAll(body)
+				require.NoError(t, err)
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			sender, err := New(WithTarget(server.URL), WithCompression(EncodingGzip, 1024))
+			require.NoError(t, err)
+
+			data := strings.Repeat("a", size)
+			e := test.MinEvent()
+			ctx := cecontext.WithRetriesConstantBackoff(context.Background(), time.Millisecond, 3)
+			result := sender.Send(ctx, binding.NewStreamMessage(&e, ioutil.NopCloser(strings.NewReader(data))))
+			var retries *RetriesResult
+			require.True(t, errors.As(result, &retries))
+			require.Equal(t, data, string(sentBody))
+			if size < 1024 {
+				// The short body was read ahead entirely, so it can be sent again
+				require.Empty(t, sentEncoding)
+				require.Equal(t, 3, retries.Retries)
+				require.Equal(t, 4, attempts)
+			} else {
+				// The compressed stream is read once, so the request isn't retried
+				require.Equal(t, EncodingGzip, sentEncoding)
+				require.Equal(t, 0, retries.Retries)
+				require.Equal(t, 1, attempts)
+			}
+		})
+	}
+}
+
+func TestDecompression_UnsupportedEncoding(t *testing.T) {
+	receiver, err := New(WithDecompression())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("data"))
+	req.Header.Set(ContentEncoding, "br")
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	require.Equal(t, "gzip, zstd", rec.Header().Get(AcceptEncoding))
+}
+
+func TestWithCompression_Invalid(t *testing.T) {
+	_, err := New(WithCompression("br", 0))
+	require.Error(t, err)
+	_, err = New(WithCompression(EncodingGzip, -1))
+	require.Error(t, err)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
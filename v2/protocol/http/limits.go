@@ -0,0 +1,44 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+)
+
+// checkRequestLimits checks the limits that can be verified before reading the body of req:
+// the declared Content-Length and the extensions carried by the headers in binary mode.
+func checkRequestLimits(req *http.Request, limits binding.Limits) error {
+	if err := limits.CheckSize(req.ContentLength); err != nil {
+		return err
+	}
+	version := specs.Version(req.Header.Get(specs.PrefixedSpecVersionName()))
+	if version == nil {
+		// Structured mode: the extensions are checked while reading the body
+		return nil
+	}
+	extensions := 0
+	for k, v := range req.Header {
+		if !strings.HasPrefix(k, prefix) || len(v) == 0 || version.Attribute(k) != nil {
+			continue
+		}
+		extensions++
+		if err := limits.CheckExtensionValue(strings.ToLower(k[len(prefix):]), v[0]); err != nil {
+			return err
+		}
+	}
+	return limits.CheckExtensions(extensions)
+}
+
+// limitedBody is a request body enforcing binding.Limits
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,82 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+func limitsTestRequest(body string, contentLength int64) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", ioutil.NopCloser(strings.NewReader(body)))
+	req.ContentLength = contentLength
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "1")
+	req.Header.Set("Ce-Type", "test")
+	req.Header.Set("Ce-Source", "/test")
+	req.Header.Set("Ce-Exta", "value")
+	req.Header.Set(ContentType, "text/plain")
+	return req
+}
+
+func TestLimits_RejectedBeforeReading(t *testing.T) {
+	tests := map[string]struct {
+		limits binding.Limits
+		req    *http.Request
+	}{
+		"content length": {
+			limits: binding.Limits{MaxEventSize: 10},
+			req:    limitsTestRequest(strings.Repeat("a", 11), 11),
+		},
+		"extensions": {
+			limits: binding.Limits{MaxExtensions: 1},
+			req: func() *http.Request {
+				req := limitsTestRequest("data", 4)
+				req.Header.Set("Ce-Extb", "value")
+				return req
+			}(),
+		},
+		"extension value": {
+			limits: binding.Limits{MaxExtensionValueLength: 4},
+			req:    limitsTestRequest("data", 4),
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			p, err := New(WithLimits(tc.limits))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, tc.req)
+			require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		})
+	}
+}
+
+func TestLimits_UnknownContentLength(t *testing.T) {
+	p, err := New(WithLimits(binding.Limits{MaxEventSize: 100}))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	go p.ServeHTTP(rec, limitsTestRequest(strings.Repeat("a", 1000), -1))
+
+	msg, fn, err := p.Respond(context.Background())
+	require.NoError(t, err)
+	_, err = binding.ToEvent(context.Background(), msg)
+	require.True(t, errors.Is(err, binding.ErrLimitExceeded))
+	require.NoError(t, fn(context.Background(), nil, protocol.NewReceipt(false, "%w", err)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
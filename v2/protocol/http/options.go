@@ -6,12 +6,17 @@
 package http
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	nethttp "net/http"
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 )
 
 // Option is the function signature required to be considered an http.Option.
@@ -83,6 +88,51 @@ func WithShutdownTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithReadHeaderTimeout sets the amount of time allowed to the http server to read the request headers.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http read header timeout option can not set nil protocol")
+		}
+		p.ReadHeaderTimeout = timeout
+		return nil
+	}
+}
+
+// WithReadTimeout sets the maximum duration for the http server to read the entire request, including the body.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http read timeout option can not set nil protocol")
+		}
+		p.ReadTimeout = timeout
+		return nil
+	}
+}
+
+// WithWriteTimeout sets the maximum duration for the http server before timing out the writes of the response.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http write timeout option can not set nil protocol")
+		}
+		p.WriteTimeout = timeout
+		return nil
+	}
+}
+
+// WithIdleTimeout sets the maximum amount of time for the http server to wait for the next request
+// when keep-alives are enabled.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http idle timeout option can not set nil protocol")
+		}
+		p.IdleTimeout = timeout
+		return nil
+	}
+}
+
 func checkListen(p *Protocol, prefix string) error {
 	switch {
 	case p.listener.Load() != nil:
@@ -139,6 +189,29 @@ func WithPath(path string) Option {
 	}
 }
 
+// WithRoute adds a path pattern to receive cloudevents on. It may be specified multiple times, and the first route
+// matching a request handles it. The segments "{name}" of the pattern match any path segment, and a last segment
+// "{name...}" matches the rest of the path. The matched pattern and the values of the parameters are available in the
+// context of the handler, see MatchedPathFrom and PathParamsFrom.
+// The middleware of the route is applied to its requests after the middleware of the Protocol, and its webhookConfig,
+// if not nil, replaces the WebhookConfig of the Protocol, including for the OPTIONS handshake.
+// When routes are configured, the Protocol only receives on its Path if it's set with WithPath.
+func WithRoute(pattern string, webhookConfig *WebhookConfig, middleware ...Middleware) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http route option can not set nil protocol")
+		}
+		r, err := newRoute(pattern)
+		if err != nil {
+			return fmt.Errorf("http route option was given an invalid pattern: %w", err)
+		}
+		r.webhookConfig = webhookConfig
+		r.handler = attachMiddleware(nethttp.HandlerFunc(p.serveHTTP), middleware)
+		p.routes = append(p.routes, r)
+		return nil
+	}
+}
+
 // WithMethod sets the HTTP verb (GET, POST, PUT, etc.) to use
 // when using an HTTP request.
 func WithMethod(method string) Option {
@@ -158,7 +231,6 @@ func WithMethod(method string) Option {
 	}
 }
 
-//
 // Middleware is a function that takes an existing http.Handler and wraps it in middleware,
 // returning the wrapped http.Handler.
 type Middleware func(next nethttp.Handler) nethttp.Handler
@@ -263,6 +335,22 @@ func WithDefaultOptionsHandlerFunc(methods []string, rate int, origins []string,
 // default retriable status codes.
 type IsRetriable func(statusCode int) bool
 
+// WithRejectionObserver adds a func invoked with the requests rejected before reaching the receiver, like the ones
+// exceeding the WebhookConfig allowed rate or failing the signature verification, and the reason of the rejection.
+// The client adds one when its ObservabilityService implements client.RejectionRecorder.
+func WithRejectionObserver(fn func(req *nethttp.Request, err error)) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http rejection observer option can not set nil protocol")
+		}
+		if fn == nil {
+			return fmt.Errorf("http rejection observer option was given a nil func")
+		}
+		p.rejectionObservers = append(p.rejectionObservers, fn)
+		return nil
+	}
+}
+
 // WithIsRetriableFunc sets the function that gets called to determine if an
 // error should be retried. If not set, the defaultIsRetriableFunc is used.
 func WithIsRetriableFunc(isRetriable IsRetriable) Option {
@@ -277,3 +365,301 @@ func WithIsRetriableFunc(isRetriable IsRetriable) Option {
 		return nil
 	}
 }
+
+// WithCompression compresses the body of the outbound requests, in binary and structured mode,
+// using the provided content encoding (EncodingGzip or EncodingZstd) when the body is at least
+// threshold bytes long. The Content-Encoding header is set accordingly.
+// The streamed data of the events sent with Client.SendStream is compressed while it's sent, without buffering it,
+// and such requests aren't retried.
+func WithCompression(encoding string, threshold int64) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http compression option can not set nil protocol")
+		}
+		if !isSupportedEncoding(encoding) {
+			return fmt.Errorf("http compression option was given an unsupported encoding: %q", encoding)
+		}
+		if threshold < 0 {
+			return fmt.Errorf("http compression option was given a negative threshold: %d", threshold)
+		}
+		p.compressionEncoding = encoding
+		p.compressionThreshold = threshold
+		return nil
+	}
+}
+
+// WithDecompression transparently decompresses the body of the inbound requests and of the responses
+// according to their Content-Encoding header, supporting EncodingGzip and EncodingZstd.
+// The receiver advertises the supported encodings through the Accept-Encoding header and replies
+// 415 Unsupported Media Type to requests with another encoding.
+func WithDecompression() Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http decompression option can not set nil protocol")
+		}
+		p.decompression = true
+		return nil
+	}
+}
+
+// WithLimits bounds the size, the number of extensions and the length of the extension values
+// of the received events. Requests whose Content-Length or headers exceed the limits are rejected
+// with 413 Request Entity Too Large before reading the body, and reading a body exceeding the
+// maximum event size fails, so the receiver replies 413 as well.
+func WithLimits(limits binding.Limits) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http limits option can not set nil protocol")
+		}
+		p.limits = &limits
+		return nil
+	}
+}
+
+// WithTLSConfig makes the http server serve HTTPS with the provided TLS config, which must provide the
+// server certificate, unless WithTLSCertificateFiles is used too. HTTP/2 is negotiated with the clients supporting it.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http TLS config option can not set nil protocol")
+		}
+		if config == nil {
+			return fmt.Errorf("http TLS config option was given a nil config")
+		}
+		p.tlsConfig = config.Clone()
+		return nil
+	}
+}
+
+// WithTLSCertificateFiles makes the http server serve HTTPS with the certificate and the key stored in the provided
+// PEM files. The files are loaded immediately, and reloaded when they are modified, to rotate the certificate
+// without restarting the server. The modifications are checked at most every 10 seconds.
+func WithTLSCertificateFiles(certFile, keyFile string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http TLS certificate files option can not set nil protocol")
+		}
+		certificate, err := newCertificateReloader(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("http TLS certificate files option failed to load the certificate: %w", err)
+		}
+		p.certificate = certificate
+		return nil
+	}
+}
+
+// WithClientCAs enables the mutual TLS authentication: the http server requires the clients to present a certificate
+// signed by one of the provided CAs. The verified client certificate is exposed in the context of the received
+// events, see PeerCertificateFrom. The server certificate must be provided with WithTLSConfig or WithTLSCertificateFiles.
+func WithClientCAs(pool *x509.CertPool) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http client CAs option can not set nil protocol")
+		}
+		if pool == nil {
+			return fmt.Errorf("http client CAs option was given a nil pool")
+		}
+		p.clientCAs = pool
+		return nil
+	}
+}
+
+// WithH2C makes the http server accept HTTP/2 over cleartext connections (h2c), as used behind service meshes
+// terminating TLS, besides HTTP/1.1. It has no effect when the server uses TLS.
+func WithH2C() Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http h2c option can not set nil protocol")
+		}
+		p.h2c = true
+		return nil
+	}
+}
+
+// WithWebhookValidation makes the sender run the validation handshake of the webhook abuse protection spec before
+// the first delivery to each target: an OPTIONS request with the WebHook-Request-Origin header set to origin, and the
+// WebHook-Request-Rate header set to requestRate, the requests per minute the sender asks for, if positive.
+// The permission granted by each target is cached. The deliveries to the targets which don't grant the permission
+// fail with ErrWebhookNotAllowed, and the deliveries are throttled to the WebHook-Allowed-Rate returned by the targets.
+// See https://github.com/cloudevents/spec/blob/v1.0/http-webhook.md#4-abuse-protection
+func WithWebhookValidation(origin string, requestRate int) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http webhook validation option can not set nil protocol")
+		}
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			return fmt.Errorf("http webhook validation option was given an empty origin")
+		}
+		if requestRate < 0 {
+			return fmt.Errorf("http webhook validation option was given an invalid request rate: %d", requestRate)
+		}
+		p.webhookValidator = newWebhookValidator(origin, requestRate)
+		return nil
+	}
+}
+
+// WithWebhookSigning signs the outbound requests following the Standard Webhooks spec:
+// https://www.standardwebhooks.com. The webhook-id header is the id of the event, and the HMAC-SHA256 signature
+// covers the exact bytes of the body, after the encoding and the compression. It's computed again with a new
+// timestamp before each retry. The secret is base64 encoded, optionally prefixed with "whsec_".
+// Streamed data, sent with Client.SendStream, is not supported: the signature requires the whole body before
+// sending it, so the send fails.
+func WithWebhookSigning(secret string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http webhook signing option can not set nil protocol")
+		}
+		key, err := parseWebhookSecret(secret)
+		if err != nil {
+			return fmt.Errorf("http webhook signing option was given an %w", err)
+		}
+		p.webhookSigner = &webhookSigner{key: key}
+		return nil
+	}
+}
+
+// WithWebhookSignatureVerification rejects the inbound requests with 401 Unauthorized unless they are signed following
+// the Standard Webhooks spec with one of secrets, which allows to rotate them, and their timestamp is within tolerance
+// of the current time, to reject replayed requests. The ids of the accepted messages are also remembered during
+// the tolerance, up to 100000 ids, to reject the requests replayed within it: beyond, the requests are rejected
+// with 503 Service Unavailable until ids expire. The id of a message which wasn't accepted with a 2xx status code
+// is forgotten, so the sender can retry it. If tolerance is not positive,
+// DefaultWebhookSignatureTolerance is used. The rejections wrap ErrInvalidWebhookSignature, see
+// WithRejectionObserver.
+func WithWebhookSignatureVerification(tolerance time.Duration, secrets ...string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http webhook signature verification option can not set nil protocol")
+		}
+		if len(secrets) == 0 {
+			return fmt.Errorf("http webhook signature verification option was given no secret")
+		}
+		keys := make([][]byte, 0, len(secrets))
+		for _, secret := range secrets {
+			key, err := parseWebhookSecret(secret)
+			if err != nil {
+				return fmt.Errorf("http webhook signature verification option was given an %w", err)
+			}
+			keys = append(keys, key)
+		}
+		if tolerance <= 0 {
+			tolerance = DefaultWebhookSignatureTolerance
+		}
+		p.webhookVerifier = &webhookVerifier{keys: keys, tolerance: tolerance, replays: newWebhookReplayCache()}
+		return nil
+	}
+}
+
+// WithBearerToken sends token as the bearer token of the requests.
+func WithBearerToken(token string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http bearer token option can not set nil protocol")
+		}
+		if token == "" {
+			return fmt.Errorf("http bearer token option was given an empty token")
+		}
+		p.tokenSource = StaticTokenSource(token)
+		return nil
+	}
+}
+
+// WithTokenSource sends the tokens of tokenSource as the bearer tokens of the requests. If tokenSource implements
+// TokenInvalidator, a request rejected with 401 Unauthorized is sent again, once, with a new token. With retries,
+// this happens on each attempt.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http token source option can not set nil protocol")
+		}
+		if tokenSource == nil {
+			return fmt.Errorf("http token source option was given a nil token source")
+		}
+		p.tokenSource = tokenSource
+		return nil
+	}
+}
+
+// WithOAuth2ClientCredentials sends the bearer tokens requested from tokenURL with the OAuth2 client credentials flow.
+// The tokens are cached and refreshed before their expiry, see ClientCredentialsTokenSource.
+func WithOAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http oauth2 client credentials option can not set nil protocol")
+		}
+		if _, err := url.Parse(tokenURL); err != nil || tokenURL == "" {
+			return fmt.Errorf("http oauth2 client credentials option was given an invalid token url: %q", tokenURL)
+		}
+		p.tokenSource = NewClientCredentialsTokenSource(tokenURL, clientID, clientSecret, scopes...)
+		return nil
+	}
+}
+
+// WithBearerTokenValidation rejects the inbound requests with 401 Unauthorized unless they carry a JWT bearer token
+// signed by one of keys, not expired, and issued by issuer for audience when they're not empty. The claims of the
+// token are available in the context of the handler, see ClaimsFrom. The rejections wrap ErrInvalidBearerToken,
+// see WithRejectionObserver.
+func WithBearerTokenValidation(keys KeySet, issuer, audience string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http bearer token validation option can not set nil protocol")
+		}
+		if keys == nil {
+			return fmt.Errorf("http bearer token validation option was given a nil key set")
+		}
+		p.tokenValidator = &tokenValidator{keys: keys, issuer: issuer, audience: audience}
+		return nil
+	}
+}
+
+// WithAsyncReceive makes the server reply 202 Accepted once a received event is parsed and validated, without
+// waiting for the handler: an invalid event is rejected with 400 Bad Request, see WithAsyncValidation to change
+// the validation. The events are queued in memory, up to queueSize events, and delivered to Respond in order. When the queue is full, the requests are rejected with 503 Service Unavailable and a Retry-After header.
+// The context of the handler keeps the values of the request context, like PathParamsFrom or ClaimsFrom, but
+// it isn't canceled with the request. The queued events are lost when the receiver stops, see WithAsyncQueue
+// to keep them.
+func WithAsyncReceive(queueSize int) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http async receive option can not set nil protocol")
+		}
+		if queueSize <= 0 {
+			return fmt.Errorf("http async receive option was given an invalid queue size: %d", queueSize)
+		}
+		p.asyncQueue = NewMemoryQueue(queueSize)
+		return nil
+	}
+}
+
+// WithAsyncQueue is WithAsyncReceive with the events queued in queue, which can persist them to survive a restart
+// of the receiver. The queue is acknowledged through the Finish of the messages it delivers.
+func WithAsyncQueue(queue AsyncQueue) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http async queue option can not set nil protocol")
+		}
+		if queue == nil {
+			return fmt.Errorf("http async queue option was given a nil queue")
+		}
+		p.asyncQueue = queue
+		return nil
+	}
+}
+
+// WithAsyncValidation replaces the validation of the events received in async mode, Event.Validate by default,
+// with validate. Since the sender is answered before the handler runs, the validation here is the one the sender
+// sees: a client with client.ValidationStrict can reject the events with Event.ValidateStrict, and a client with
+// client.ValidationLenient can accept the events failing Event.Validate with a validation only requiring a context.
+func WithAsyncValidation(validate func(e *event.Event) error) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http async validation option can not set nil protocol")
+		}
+		if validate == nil {
+			return fmt.Errorf("http async validation option was given a nil validation")
+		}
+		p.asyncValidate = validate
+		return nil
+	}
+}
@@ -8,11 +8,14 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,7 +32,7 @@ const (
 )
 
 type msgErr struct {
-	msg    *Message
+	msg    binding.Message
 	respFn protocol.ResponseFn
 	err    error
 }
@@ -69,6 +72,13 @@ type Protocol struct {
 	// If 0, DefaultShutdownTimeout is used.
 	ShutdownTimeout time.Duration
 
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout are passed to the http.Server,
+	// see its documentation. If 0, the http.Server defaults are used.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
 	// Port is the port configured to bind the receiver to. Defaults to 8080.
 	// If you want to know the effective port you're listening to, use GetListeningPort()
 	Port int
@@ -88,6 +98,37 @@ type Protocol struct {
 	middleware        []Middleware
 
 	isRetriableFunc IsRetriable
+
+	compressionEncoding  string
+	compressionThreshold int64
+	decompression        bool
+
+	limits *binding.Limits
+
+	tlsConfig   *tls.Config
+	certificate *certificateReloader
+	clientCAs   *x509.CertPool
+	h2c         bool
+
+	webhookValidator   *webhookValidator
+	webhookLimiters    rateLimiters
+	rejectionObservers []func(*http.Request, error)
+
+	webhookSigner   *webhookSigner
+	webhookVerifier *webhookVerifier
+
+	tokenSource    TokenSource
+	tokenValidator *tokenValidator
+
+	routes []*route
+
+	asyncQueue AsyncQueue
+	// asyncValidate validates the events before they're queued, Event.Validate when nil
+	asyncValidate func(*event.Event) error
+	// asyncPumped receives the events popped from a persistent asyncQueue until asyncCancel is called
+	asyncMu     sync.Mutex
+	asyncPumped chan binding.Message
+	asyncCancel context.CancelFunc
 }
 
 func New(opts ...Option) (*Protocol, error) {
@@ -175,10 +216,33 @@ func (p *Protocol) Request(ctx context.Context, m binding.Message, transformers
 		return nil, fmt.Errorf("not initialized: %#v", p)
 	}
 
+	if p.webhookValidator != nil {
+		if err = p.webhookValidator.validate(ctx, p.Client, req); err != nil {
+			return nil, protocol.NewReceipt(false, "%w", err)
+		}
+		if req.Header == nil {
+			req.Header = http.Header{}
+		}
+		req.Header.Set("WebHook-Request-Origin", p.webhookValidator.origin)
+	}
+
 	if err = WriteRequest(ctx, m, req, transformers...); err != nil {
 		return nil, err
 	}
 
+	if p.compressionEncoding != "" {
+		if err = compressBody(req, p.compressionEncoding, p.compressionThreshold); err != nil {
+			return nil, err
+		}
+	}
+
+	// The signature covers the exact bytes sent, it's computed before each attempt in doOnce
+	if p.webhookSigner != nil {
+		if err = p.webhookSigner.prepare(req, m); err != nil {
+			return nil, err
+		}
+	}
+
 	return p.do(ctx, req)
 }
 
@@ -257,8 +321,15 @@ func (p *Protocol) Respond(ctx context.Context) (binding.Message, protocol.Respo
 	if ctx == nil {
 		return nil, nil, fmt.Errorf("nil Context")
 	}
+	var async <-chan binding.Message
+	if p.asyncQueue != nil {
+		async = p.asyncMessages()
+	}
 
 	select {
+	case m := <-async:
+		return m, noAsyncRespFn, nil
+
 	case in, ok := <-p.incoming:
 		if !ok {
 			return nil, nil, io.EOF
@@ -277,14 +348,39 @@ func (p *Protocol) Respond(ctx context.Context) (binding.Message, protocol.Respo
 // ServeHTTP implements http.Handler.
 // Blocks until ResponseFn is invoked.
 func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if m := p.matchRoute(req.URL.Path); m != nil {
+		m.route.handler.ServeHTTP(rw, req.WithContext(withMatchedRoute(req.Context(), m)))
+		return
+	}
+	if len(p.routes) > 0 && (strings.TrimSpace(p.Path) == "" || req.URL.Path != p.GetPath()) {
+		// The Protocol is only registered for its routes, or the request matched the subtree of a route
+		// with parameters without matching the route
+		http.NotFound(rw, req)
+		return
+	}
+	p.serveHTTP(rw, req.WithContext(withMatchedRoute(req.Context(), &matchedRoute{route: &route{pattern: p.GetPath()}})))
+}
+
+func (p *Protocol) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	webhookConfig, _ := p.webhookConfigFor(req.Context())
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+		req = req.WithContext(withPeerCertificate(req.Context(), req.TLS.VerifiedChains[0][0]))
+	}
+	if p.decompression {
+		rw.Header().Set(AcceptEncoding, acceptEncoding)
+	}
+
 	// Filter the GET style methods:
 	switch req.Method {
 	case http.MethodOptions:
-		if p.OptionsHandlerFn == nil {
+		if p.OptionsHandlerFn != nil {
+			p.OptionsHandlerFn(rw, req)
+		} else if webhookConfig != p.WebhookConfig {
+			// The routes with their own WebhookConfig run the handshake
+			p.OptionsHandler(rw, req)
+		} else {
 			rw.WriteHeader(http.StatusMethodNotAllowed)
-			return
 		}
-		p.OptionsHandlerFn(rw, req)
 		return
 
 	case http.MethodGet:
@@ -304,6 +400,58 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if webhookConfig != nil {
+		if err := p.checkWebhookRequest(rw, req); err != nil {
+			p.reportRejection(req, err)
+			return
+		}
+	}
+
+	if p.tokenValidator != nil {
+		claims, err := p.tokenValidator.validateRequest(req)
+		if err != nil {
+			rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(rw, fmt.Sprintf("Cannot accept CloudEvent: %s", err), http.StatusUnauthorized)
+			p.reportRejection(req, err)
+			return
+		}
+		req = req.WithContext(withClaims(req.Context(), claims))
+	}
+
+	if p.limits != nil {
+		if err := checkRequestLimits(req, *p.limits); err != nil {
+			http.Error(rw, fmt.Sprintf("Cannot accept CloudEvent: %s", err), http.StatusRequestEntityTooLarge)
+			return
+		}
+	}
+
+	if p.webhookVerifier != nil {
+		if code, err := p.webhookVerifier.verifyRequest(req, p.limits); err != nil {
+			http.Error(rw, fmt.Sprintf("Cannot accept CloudEvent: %s", err), code)
+			p.reportRejection(req, err)
+			return
+		}
+		sw := &statusResponseWriter{ResponseWriter: rw}
+		rw = sw
+		defer func() {
+			p.webhookVerifier.delivered(req, sw.status)
+		}()
+	}
+
+	if p.decompression && req.Body != nil {
+		body, err := decompressBody(req.Header, req.Body)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("Cannot decompress CloudEvent: %s", err), http.StatusUnsupportedMediaType)
+			return
+		}
+		req.Body = body
+	}
+
+	if p.limits != nil && req.Body != nil {
+		// Limit the decompressed body too
+		req.Body = &limitedBody{Reader: p.limits.LimitReader(req.Body), Closer: req.Body}
+	}
+
 	m := NewMessageFromHttpRequest(req)
 	if m == nil {
 		// Should never get here unless ServeHTTP is called directly.
@@ -312,6 +460,11 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return // if there was no message, return.
 	}
 
+	if p.asyncQueue != nil {
+		p.serveAsync(rw, req, m)
+		return
+	}
+
 	var finishErr error
 	m.OnFinish = func(err error) error {
 		finishErr = err
@@ -351,6 +504,8 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 					return validationError
 				} else if errors.Is(res, binding.ErrUnknownEncoding) {
 					status = http.StatusUnsupportedMediaType
+				} else if errors.Is(res, binding.ErrLimitExceeded) {
+					status = http.StatusRequestEntityTooLarge
 				} else {
 					status = http.StatusInternalServerError
 				}
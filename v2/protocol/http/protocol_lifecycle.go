@@ -12,6 +12,9 @@ import (
 	"net/http"
 	"strings"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
@@ -27,7 +30,9 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 
 	if !p.handlerRegistered {
 		// handler.Handle might panic if the user tries to use the same path as the sdk.
-		p.Handler.Handle(p.GetPath(), p)
+		for _, pattern := range p.muxPatterns() {
+			p.Handler.Handle(pattern, p)
+		}
 		p.handlerRegistered = true
 	}
 
@@ -37,20 +42,37 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 		return err
 	}
 
+	handler := attachMiddleware(p.Handler, p.middleware)
+	if p.h2c {
+		handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: p.IdleTimeout})
+	}
+
+	tlsConfig := p.serverTLSConfig()
 	p.server = &http.Server{
-		Addr:    listener.Addr().String(),
-		Handler: attachMiddleware(p.Handler, p.middleware),
+		Addr:              listener.Addr().String(),
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: p.ReadHeaderTimeout,
+		ReadTimeout:       p.ReadTimeout,
+		WriteTimeout:      p.WriteTimeout,
+		IdleTimeout:       p.IdleTimeout,
 	}
 
 	// Shutdown
 	defer func() {
 		_ = p.server.Close()
 		p.server = nil
+		p.stopAsyncPump()
 	}()
 
 	errChan := make(chan error)
 	go func() {
-		errChan <- p.server.Serve(listener)
+		if tlsConfig != nil {
+			// The certificates are provided by the TLS config
+			errChan <- p.server.ServeTLS(listener, "", "")
+		} else {
+			errChan <- p.server.Serve(listener)
+		}
 	}()
 
 	// wait for the server to return or ctx.Done().
@@ -132,6 +154,26 @@ func (p *Protocol) GetPath() string {
 	return "/" // default
 }
 
+// muxPatterns returns the patterns registering the Protocol on its Handler: its Path, unless only routes are configured,
+// and the patterns of its routes
+func (p *Protocol) muxPatterns() []string {
+	var patterns []string
+	registered := make(map[string]bool)
+	add := func(pattern string) {
+		if !registered[pattern] {
+			registered[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(p.routes) == 0 || strings.TrimSpace(p.Path) != "" {
+		add(p.GetPath())
+	}
+	for _, r := range p.routes {
+		add(r.muxPattern())
+	}
+	return patterns
+}
+
 // attachMiddleware attaches the HTTP middleware to the specified handler.
 func attachMiddleware(h http.Handler, middleware []Middleware) http.Handler {
 	for _, m := range middleware {
@@ -8,8 +8,11 @@ package http
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,24 +22,104 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
+// RetryAfter is the header used by the receivers to request a delay before retrying
+const RetryAfter = "Retry-After"
+
 func (p *Protocol) do(ctx context.Context, req *http.Request) (binding.Message, error) {
 	params := cecontext.RetriesFrom(ctx)
 
 	switch params.Strategy {
-	case cecontext.BackoffStrategyConstant, cecontext.BackoffStrategyLinear, cecontext.BackoffStrategyExponential:
+	case cecontext.BackoffStrategyConstant, cecontext.BackoffStrategyLinear, cecontext.BackoffStrategyExponential,
+		cecontext.BackoffStrategyFullJitter, cecontext.BackoffStrategyDecorrelatedJitter:
 		return p.doWithRetry(ctx, params, req)
 	case cecontext.BackoffStrategyNone:
 		fallthrough
 	default:
-		return p.doOnce(req)
+		return p.doOnce(req, params.AttemptTimeout)
 	}
 }
 
-func (p *Protocol) doOnce(req *http.Request) (binding.Message, protocol.Result) {
+// doOnce sends req with the token of the TokenSource, if any. When the receiver rejects the token with 401
+// Unauthorized, the token is refreshed and req is sent again, once.
+func (p *Protocol) doOnce(req *http.Request, timeout time.Duration) (binding.Message, protocol.Result) {
+	if p.tokenSource == nil {
+		return p.doAttempt(req, timeout)
+	}
+
+	token, err := p.authorize(req)
+	if err != nil {
+		return nil, protocol.NewReceipt(false, "%w", err)
+	}
+	msg, result := p.doAttempt(req, timeout)
+
+	invalidator, ok := p.tokenSource.(TokenInvalidator)
+	var httpResult *Result
+	if !ok || !errors.As(result, &httpResult) || httpResult.StatusCode != http.StatusUnauthorized {
+		return msg, result
+	}
+	invalidator.Invalidate(token)
+	if req.Body != nil && req.GetBody == nil {
+		// The body was consumed and can't be sent again
+		return msg, result
+	}
+
+	cecontext.LoggerFrom(req.Context()).Debug("token rejected, sending the request again with a new token")
+	if msg != nil {
+		_ = msg.Finish(nil)
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, protocol.NewReceipt(false, "%w", err)
+		}
+		req.Body = body
+	}
+	if _, err := p.authorize(req); err != nil {
+		return nil, protocol.NewReceipt(false, "%w", err)
+	}
+	return p.doAttempt(req, timeout)
+}
+
+// doAttempt sends req. If timeout is positive, it bounds the request, including the read of the response body.
+func (p *Protocol) doAttempt(req *http.Request, timeout time.Duration) (binding.Message, protocol.Result) {
+	if p.webhookValidator != nil {
+		if err := p.webhookValidator.throttle(req); err != nil {
+			return nil, protocol.NewReceipt(false, "%w", err)
+		}
+	}
+
+	if p.webhookSigner != nil {
+		if err := p.webhookSigner.sign(req, time.Now()); err != nil {
+			return nil, protocol.NewReceipt(false, "%w", err)
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+		req = req.WithContext(ctx)
+	}
+
 	resp, err := p.Client.Do(req)
 	if err != nil {
+		cancel()
 		return nil, protocol.NewReceipt(false, "%w", err)
 	}
+	if resp.Body != nil {
+		resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	} else {
+		cancel()
+	}
+
+	if p.decompression {
+		body, err := decompressBody(resp.Header, resp.Body)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, protocol.NewReceipt(false, "%w", err)
+		}
+		resp.Body = body
+	}
 
 	var result protocol.Result
 	if resp.StatusCode/100 == 2 {
@@ -52,15 +135,22 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 	then := time.Now()
 	retry := 0
 	results := make([]protocol.Result, 0)
+	var backoff time.Duration
 
 	for {
-		msg, result := p.doOnce(req)
+		msg, result := p.doOnce(req, params.AttemptTimeout)
 
 		// Fast track common case.
 		if protocol.IsACK(result) {
 			return msg, NewRetriesResult(result, retry, then, results)
 		}
 
+		if req.Body != nil && req.GetBody == nil {
+			// The body was consumed and can't be sent again
+			cecontext.LoggerFrom(ctx).Debug("body can't be sent again, will not try again")
+			return msg, NewRetriesResult(result, retry, then, results)
+		}
+
 		// Try again?
 		//
 		// Make sure the error was something we should retry.
@@ -90,10 +180,17 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 		}
 
 	DoBackoff:
-		// Wait for the correct amount of backoff time.
+		// Wait for the correct amount of backoff time, or for the time requested by the receiver.
 
 		// total tries = retry + 1
-		if err := params.Backoff(ctx, retry+1); err != nil {
+		backoff = params.NextBackoff(retry+1, backoff)
+		delay := backoff
+		if retryAfter, ok := retryAfterFrom(msg, result, time.Now()); ok {
+			delay = params.RetryAfter(retryAfter)
+			// The next decorrelated jitter step starts from the time actually waited
+			backoff = delay
+		}
+		if err := params.BackoffWith(ctx, retry+1, delay); err != nil {
 			// do not try again.
 			cecontext.LoggerFrom(ctx).Debugw("backoff error, will not try again", zap.Error(err))
 			return msg, NewRetriesResult(result, retry, then, results)
@@ -101,5 +198,55 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 
 		retry++
 		results = append(results, result)
+
+		// Release the response of the previous attempt
+		if msg != nil {
+			_ = msg.Finish(nil)
+		}
+
+		// Rewind the body, already consumed by the previous attempt
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return msg, NewRetriesResult(result, retry, then, results)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryAfterFrom returns the delay requested by the Retry-After header of a 429 or 503 response,
+// as a number of seconds or as an HTTP-date
+func retryAfterFrom(msg binding.Message, result protocol.Result, now time.Time) (time.Duration, bool) {
+	var httpResult *Result
+	if !errors.As(result, &httpResult) ||
+		(httpResult.StatusCode != http.StatusTooManyRequests && httpResult.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	m, ok := msg.(*Message)
+	if !ok {
+		return 0, false
+	}
+	value := strings.TrimSpace(m.Header.Get(RetryAfter))
+	if value == "" {
+		return 0, false
 	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now), true
+	}
+	return 0, false
+}
+
+// cancelOnCloseBody cancels the context of the request when the response body is closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
 }
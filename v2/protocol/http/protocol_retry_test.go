@@ -10,7 +10,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -180,3 +182,126 @@ func TestRequestWithRetries_linear(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestWithRetries_retryAfter(t *testing.T) {
+	dummyEvent := event.New()
+	testCases := map[string]struct {
+		statusCode    int
+		retryAfter    string
+		retryAfterIn  time.Duration // Retry-After as an HTTP-date, relative to the response time
+		maxRetryAfter time.Duration
+		wantMin       time.Duration
+		wantMax       time.Duration
+	}{
+		"429, seconds": {
+			statusCode: 429,
+			retryAfter: "1",
+			wantMin:    time.Second,
+			wantMax:    2 * time.Second,
+		},
+		"503, seconds capped": {
+			statusCode:    503,
+			retryAfter:    "3600",
+			maxRetryAfter: 100 * time.Millisecond,
+			wantMin:       100 * time.Millisecond,
+			wantMax:       time.Second,
+		},
+		"503, http date": {
+			statusCode: 503,
+			// the header has a precision of one second, so the delay is between 1 and 2 seconds
+			retryAfterIn: 2 * time.Second,
+			wantMin:      900 * time.Millisecond,
+			wantMax:      3 * time.Second,
+		},
+		"503, date in the past": {
+			statusCode:   503,
+			retryAfterIn: -time.Hour,
+			wantMax:      time.Second,
+		},
+		"425, ignored": {
+			statusCode: 425,
+			retryAfter: "3600",
+			wantMax:    time.Second,
+		},
+		"503, invalid": {
+			statusCode: 503,
+			retryAfter: "soon",
+			wantMax:    time.Second,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			requestCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				requestCount++
+				if requestCount == 1 {
+					retryAfter := tc.retryAfter
+					if tc.retryAfterIn != 0 {
+						retryAfter = time.Now().Add(tc.retryAfterIn).UTC().Format(http.TimeFormat)
+					}
+					rw.Header().Set(RetryAfter, retryAfter)
+					rw.WriteHeader(tc.statusCode)
+					return
+				}
+				rw.WriteHeader(200)
+			}))
+			defer server.Close()
+
+			p, err := New(WithTarget(server.URL), WithClient(http.Client{}))
+			require.NoError(t, err)
+
+			ctx := cecontext.WithRetryParams(context.Background(), &cecontext.RetryParams{
+				Strategy:      cecontext.BackoffStrategyConstant,
+				Period:        time.Nanosecond,
+				MaxTries:      3,
+				MaxRetryAfter: tc.maxRetryAfter,
+			})
+			start := time.Now()
+			msg, result := p.Request(ctx, binding.ToMessage(&dummyEvent))
+			elapsed := time.Since(start)
+
+			require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+			require.NoError(t, msg.Finish(nil))
+			require.Equal(t, 2, requestCount)
+			require.True(t, elapsed >= tc.wantMin && elapsed < tc.wantMax, "unexpected delay %v", elapsed)
+		})
+	}
+}
+
+func TestRequestWithRetries_attemptTimeout(t *testing.T) {
+	dummyEvent := event.New()
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			// Hang until the client gives up the attempt
+			<-req.Context().Done()
+			return
+		}
+		rw.WriteHeader(200)
+		_, _ = rw.Write([]byte("response"))
+	}))
+	defer server.Close()
+
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = cecontext.WithRetryParams(ctx, &cecontext.RetryParams{
+		Strategy:       cecontext.BackoffStrategyFullJitter,
+		Period:         time.Millisecond,
+		MaxTries:       3,
+		AttemptTimeout: 100 * time.Millisecond,
+	})
+	msg, result := p.Request(ctx, binding.ToMessage(&dummyEvent))
+	require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	require.Equal(t, 2, requestCount)
+	require.Equal(t, 1, result.(*RetriesResult).Retries)
+
+	// The response body can be read after the attempt returned
+	body, err := ioutil.ReadAll(msg.(*Message).BodyReader)
+	require.NoError(t, err)
+	require.Equal(t, "response", string(body))
+	require.NoError(t, msg.Finish(nil))
+}
@@ -0,0 +1,161 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type routeKey int
+
+const (
+	matchedRouteKey routeKey = iota
+)
+
+// route is a path pattern receiving events, with its own middleware and WebhookConfig
+type route struct {
+	pattern  string
+	segments []string
+	// handler serves the requests matching the route, through its middleware
+	handler       http.Handler
+	webhookConfig *WebhookConfig
+	limiters      rateLimiters
+}
+
+// matchedRoute is the route which matched a request, with the values of its path parameters
+type matchedRoute struct {
+	route  *route
+	params map[string]string
+}
+
+// MatchedPathFrom returns the path pattern of the route which received the event, see WithRoute.
+// Returns the Path of the Protocol if the event was received on it, and "" if the event wasn't received
+// by the Protocol.
+func MatchedPathFrom(ctx context.Context) string {
+	if m, ok := ctx.Value(matchedRouteKey).(*matchedRoute); ok {
+		return m.route.pattern
+	}
+	return ""
+}
+
+// PathParamsFrom returns the values of the path parameters of the route which received the event, see WithRoute.
+// Returns nil if the route has no parameter or if the event wasn't received by the Protocol.
+func PathParamsFrom(ctx context.Context) map[string]string {
+	if m, ok := ctx.Value(matchedRouteKey).(*matchedRoute); ok {
+		return m.params
+	}
+	return nil
+}
+
+func withMatchedRoute(ctx context.Context, m *matchedRoute) context.Context {
+	return context.WithValue(ctx, matchedRouteKey, m)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// newRoute parses pattern: its segments "{name}" match any segment, and a last segment "{name...}"
+// matches the rest of the path, possibly empty
+func newRoute(pattern string) (*route, error) {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("the pattern %q must start with /", pattern)
+	}
+	segments := splitPath(pattern)
+	names := make(map[string]bool)
+	for i, s := range segments {
+		if !strings.HasPrefix(s, "{") && !strings.HasSuffix(s, "}") {
+			if strings.ContainsAny(s, "{}") {
+				return nil, fmt.Errorf("invalid segment %q in pattern %q", s, pattern)
+			}
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+		if strings.HasSuffix(name, "...") {
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("the parameter %q must be the last segment of pattern %q", s, pattern)
+			}
+			name = strings.TrimSuffix(name, "...")
+		}
+		if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' || name == "" || strings.ContainsAny(name, "{}") {
+			return nil, fmt.Errorf("invalid segment %q in pattern %q", s, pattern)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate parameter %q in pattern %q", name, pattern)
+		}
+		names[name] = true
+	}
+	return &route{pattern: pattern, segments: segments}, nil
+}
+
+// match returns the values of the parameters if path matches the route
+func (r *route) match(path string) (map[string]string, bool) {
+	segments := splitPath(path)
+	var params map[string]string
+	for i, s := range r.segments {
+		if strings.HasSuffix(s, "...}") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[s[1:len(s)-4]] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if s[0] == '{' {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[s[1:len(s)-1]] = segments[i]
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	return params, true
+}
+
+// muxPattern returns the pattern registering the route on a http.ServeMux: the path itself without parameters,
+// or the subtree of its static prefix
+func (r *route) muxPattern() string {
+	static := make([]string, 0, len(r.segments))
+	for _, s := range r.segments {
+		if s[0] == '{' {
+			return "/" + strings.Join(append(static, ""), "/")
+		}
+		static = append(static, s)
+	}
+	return "/" + strings.Join(static, "/")
+}
+
+// matchRoute returns the first route matching path
+func (p *Protocol) matchRoute(path string) *matchedRoute {
+	for _, r := range p.routes {
+		if params, ok := r.match(path); ok {
+			return &matchedRoute{route: r, params: params}
+		}
+	}
+	return nil
+}
+
+// webhookConfigFor returns the WebhookConfig applying to the request with ctx, and the rate limiters enforcing it
+func (p *Protocol) webhookConfigFor(ctx context.Context) (*WebhookConfig, *rateLimiters) {
+	if m, ok := ctx.Value(matchedRouteKey).(*matchedRoute); ok && m.route.webhookConfig != nil {
+		return m.route.webhookConfig, &m.route.limiters
+	}
+	return p.WebhookConfig, &p.webhookLimiters
+}
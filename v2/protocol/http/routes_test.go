@@ -0,0 +1,168 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute_match(t *testing.T) {
+	testCases := []struct {
+		pattern    string
+		path       string
+		wantMatch  bool
+		wantParams map[string]string
+	}{
+		{pattern: "/orders", path: "/orders", wantMatch: true},
+		{pattern: "/orders", path: "/orders/", wantMatch: true},
+		{pattern: "/orders", path: "/payments"},
+		{pattern: "/orders", path: "/orders/1"},
+		{pattern: "/", path: "/", wantMatch: true},
+		{pattern: "/", path: "/orders"},
+		{pattern: "/orders/{id}", path: "/orders/1", wantMatch: true, wantParams: map[string]string{"id": "1"}},
+		{pattern: "/orders/{id}", path: "/orders"},
+		{pattern: "/orders/{id}", path: "/orders/1/items"},
+		{
+			pattern:    "/{tenant}/orders/{id}",
+			path:       "/acme/orders/1",
+			wantMatch:  true,
+			wantParams: map[string]string{"tenant": "acme", "id": "1"},
+		},
+		{pattern: "/files/{path...}", path: "/files/a/b/c", wantMatch: true, wantParams: map[string]string{"path": "a/b/c"}},
+		{pattern: "/files/{path...}", path: "/files", wantMatch: true, wantParams: map[string]string{"path": ""}},
+		{pattern: "/files/{path...}", path: "/other/a"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			r, err := newRoute(tc.pattern)
+			require.NoError(t, err)
+			params, ok := r.match(tc.path)
+			require.Equal(t, tc.wantMatch, ok)
+			require.Equal(t, tc.wantParams, params)
+		})
+	}
+}
+
+func TestNewRoute_invalid(t *testing.T) {
+	for _, pattern := range []string{"orders", "/orders/{}", "/orders/{id", "/orders/id}", "/a{id}", "/{rest...}/a", "/{id}/{id}"} {
+		_, err := newRoute(pattern)
+		require.Error(t, err, pattern)
+	}
+	_, err := New(WithRoute("orders", nil))
+	require.EqualError(t, err, `http route option was given an invalid pattern: the pattern "orders" must start with /`)
+}
+
+func TestRoute_muxPattern(t *testing.T) {
+	for pattern, want := range map[string]string{
+		"/":                   "/",
+		"/orders":             "/orders",
+		"/orders/{id}":        "/orders/",
+		"/orders/{id}/items":  "/orders/",
+		"/{tenant}/orders":    "/",
+		"/files/{path...}":    "/files/",
+		"/webhooks/v1/orders": "/webhooks/v1/orders",
+	} {
+		r, err := newRoute(pattern)
+		require.NoError(t, err)
+		require.Equal(t, want, r.muxPattern(), pattern)
+	}
+}
+
+func TestWithRoute(t *testing.T) {
+	header := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Route", name)
+				next.ServeHTTP(rw, req)
+			})
+		}
+	}
+	url, received := startTestServer(t, "http",
+		WithRoute("/orders", nil, header("orders")),
+		WithRoute("/payments/{id}", &WebhookConfig{AllowedOrigins: []string{"https://payments.example.com"}}, header("payments")),
+	)
+
+	resp, err := postTestEvent(http.DefaultClient, url+"/orders")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "orders", resp.Header.Get("X-Route"))
+	ctx := <-received
+	require.Equal(t, "/orders", MatchedPathFrom(ctx))
+	require.Nil(t, PathParamsFrom(ctx))
+
+	// The Protocol isn't registered on the default path
+	resp, err = postTestEvent(http.DefaultClient, url+"/other")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, err = postTestEvent(http.DefaultClient, url+"/orders/1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// The route runs the handshake with its WebhookConfig
+	req, err := http.NewRequest(http.MethodOptions, url+"/payments/1", nil)
+	require.NoError(t, err)
+	req.Header.Set("WebHook-Request-Origin", "https://payments.example.com")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "https://payments.example.com", resp.Header.Get("WebHook-Allowed-Origin"))
+	req, err = http.NewRequest(http.MethodOptions, url+"/orders", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodPost, url+"/payments/42", nil)
+	require.NoError(t, err)
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "1")
+	req.Header.Set("ce-type", "type")
+	req.Header.Set("ce-source", "source")
+	req.Header.Set("WebHook-Request-Origin", "https://payments.example.com")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "payments", resp.Header.Get("X-Route"))
+	ctx = <-received
+	require.Equal(t, "/payments/{id}", MatchedPathFrom(ctx))
+	require.Equal(t, map[string]string{"id": "42"}, PathParamsFrom(ctx))
+
+	// The WebhookConfig of the route is enforced
+	resp, err = postTestEvent(http.DefaultClient, url+"/payments/43")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestWithRoute_path(t *testing.T) {
+	url, received := startTestServer(t, "http", WithPath("/events"), WithRoute("/orders/{id}", nil))
+
+	resp, err := postTestEvent(http.DefaultClient, url+"/events")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/events", MatchedPathFrom(<-received))
+
+	resp, err = postTestEvent(http.DefaultClient, url+"/orders/1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/orders/{id}", MatchedPathFrom(<-received))
+
+	// The subtree of the route with a parameter doesn't fall back to the Path
+	resp, err = postTestEvent(http.DefaultClient, url+"/orders/1/2")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, err = postTestEvent(http.DefaultClient, url+"/orders/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	require.Equal(t, "", MatchedPathFrom(context.Background()))
+}
@@ -0,0 +1,123 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type tlsKey int
+
+const (
+	peerCertificateKey tlsKey = iota
+)
+
+// PeerCertificateFrom returns the verified certificate of the client which sent the request
+// delivering the event, when the Protocol verifies the client certificates with WithClientCAs.
+// Returns nil otherwise.
+func PeerCertificateFrom(ctx context.Context) *x509.Certificate {
+	if cert, ok := ctx.Value(peerCertificateKey).(*x509.Certificate); ok {
+		return cert
+	}
+	return nil
+}
+
+func withPeerCertificate(ctx context.Context, cert *x509.Certificate) context.Context {
+	return context.WithValue(ctx, peerCertificateKey, cert)
+}
+
+// serverTLSConfig returns the TLS config of the server, or nil if the server doesn't use TLS
+func (p *Protocol) serverTLSConfig() *tls.Config {
+	if p.tlsConfig == nil && p.certificate == nil && p.clientCAs == nil {
+		return nil
+	}
+
+	var config *tls.Config
+	if p.tlsConfig != nil {
+		config = p.tlsConfig.Clone()
+	} else {
+		config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if p.certificate != nil {
+		config.GetCertificate = p.certificate.GetCertificate
+	}
+	if p.clientCAs != nil {
+		config.ClientCAs = p.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config
+}
+
+// certificateCheckInterval is the minimal delay between two checks of the certificate files
+const certificateCheckInterval = 10 * time.Second
+
+// certificateReloader loads a certificate and its key from files, reloading them when they're modified.
+// The files are checked at most once per certificateCheckInterval, during a handshake, the other handshakes
+// are served with the cached certificate without locking.
+type certificateReloader struct {
+	// checked is the time of the last check, in Unix nanoseconds. It's the first field to be 64-bit aligned.
+	checked int64
+
+	certFile string
+	keyFile  string
+
+	certificate atomic.Value // *tls.Certificate
+
+	mu          sync.Mutex
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{certFile: certFile, keyFile: keyFile, checked: time.Now().UnixNano()}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate. If the new files can't be loaded, the previous certificate
+// is kept, so a partially written certificate doesn't break the server.
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	now := time.Now().UnixNano()
+	checked := atomic.LoadInt64(&r.checked)
+	if now-checked >= int64(certificateCheckInterval) && atomic.CompareAndSwapInt64(&r.checked, checked, now) {
+		r.mu.Lock()
+		_ = r.reload()
+		r.mu.Unlock()
+	}
+	return r.certificate.Load().(*tls.Certificate), nil
+}
+
+// reload loads the files if they were modified since the last successful load. r.mu must be held, or r not shared yet.
+func (r *certificateReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return err
+	}
+	if r.certificate.Load() != nil && certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.certificate.Store(&certificate)
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return nil
+}
@@ -0,0 +1,292 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+// newTestCertificate creates a certificate signed by parent, or self-signed if parent is nil
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCertificate{
+		cert: cert,
+		key:  key,
+		tls:  tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert},
+	}
+}
+
+func (c *testCertificate) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.cert)
+	return pool
+}
+
+func (c *testCertificate) writeFiles(t *testing.T, dir string) (string, string) {
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+// startTestServer starts p, returning its base URL and a channel receiving the handler contexts
+func startTestServer(t *testing.T, scheme string, opts ...Option) (string, <-chan context.Context) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	p, err := New(append(opts, WithListener(listener))...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = p.OpenInbound(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	received := make(chan context.Context, 1)
+	go func() {
+		for {
+			msg, respFn, err := p.Respond(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case received <- msg.(*Message).Context():
+			default:
+			}
+			_ = msg.Finish(nil)
+			_ = respFn(ctx, nil, nil)
+		}
+	}()
+
+	return scheme + "://" + listener.Addr().String(), received
+}
+
+func postTestEvent(client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader("hello"))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", "1")
+	req.Header.Set("ce-type", "type")
+	req.Header.Set("ce-source", "source")
+	resp, err := client.Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestTLS(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil)
+	server := newTestCertificate(t, "server", ca)
+
+	url, received := startTestServer(t, "https", WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{server.tls}}))
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: ca.pool()},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := postTestEvent(client, url)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Nil(t, PeerCertificateFrom(<-received))
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil)
+	server := newTestCertificate(t, "server", ca)
+	client := newTestCertificate(t, "my-client", ca)
+	otherCA := newTestCertificate(t, "other-ca", nil)
+	otherClient := newTestCertificate(t, "other-client", otherCA)
+
+	url, received := startTestServer(t, "https",
+		WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{server.tls}}),
+		WithClientCAs(ca.pool()),
+	)
+
+	newClient := func(certificates ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: ca.pool(), Certificates: certificates},
+		}}
+	}
+
+	_, err := postTestEvent(newClient(), url)
+	require.Error(t, err)
+	_, err = postTestEvent(newClient(otherClient.tls), url)
+	require.Error(t, err)
+
+	resp, err := postTestEvent(newClient(client.tls), url)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	peer := PeerCertificateFrom(<-received)
+	require.NotNil(t, peer)
+	require.Equal(t, "my-client", peer.Subject.CommonName)
+}
+
+func TestTLSCertificateFilesReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCertificate(t, "ca", nil)
+	first := newTestCertificate(t, "first", ca)
+	second := newTestCertificate(t, "second", ca)
+
+	_, err = New(WithTLSCertificateFiles(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")))
+	require.Error(t, err)
+
+	certFile, keyFile := first.writeFiles(t, dir)
+	url, _ := startTestServer(t, "https", WithTLSCertificateFiles(certFile, keyFile))
+
+	servedCertificate := func() string {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool()}}}
+		resp, err := postTestEvent(client, url)
+		require.NoError(t, err)
+		return resp.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	require.Equal(t, "first", servedCertificate())
+
+	reloader, err := newCertificateReloader(certFile, keyFile)
+	require.NoError(t, err)
+	reloadedCertificate := func() string {
+		certificate, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+
+	second.writeFiles(t, dir)
+	// Make sure the modification time changes on file systems with a coarse resolution
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	require.NoError(t, os.Chtimes(keyFile, future, future))
+
+	// The files are checked again once the interval elapsed
+	require.Equal(t, "first", reloadedCertificate())
+	atomic.AddInt64(&reloader.checked, -int64(certificateCheckInterval))
+	require.Equal(t, "second", reloadedCertificate())
+}
+
+func TestH2C(t *testing.T) {
+	url, received := startTestServer(t, "http", WithH2C())
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	resp, err := postTestEvent(client, url)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, resp.ProtoMajor)
+	<-received
+
+	// HTTP/1.1 is still served
+	resp, err = postTestEvent(&http.Client{Transport: &http.Transport{}}, url)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, resp.ProtoMajor)
+}
+
+func TestServerTimeouts(t *testing.T) {
+	p, err := New(
+		WithReadHeaderTimeout(time.Second),
+		WithReadTimeout(2*time.Second),
+		WithWriteTimeout(3*time.Second),
+		WithIdleTimeout(4*time.Second),
+	)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, p.ReadHeaderTimeout)
+	require.Equal(t, 2*time.Second, p.ReadTimeout)
+	require.Equal(t, 3*time.Second, p.WriteTimeout)
+	require.Equal(t, 4*time.Second, p.IdleTimeout)
+
+	// The server closes the connections which don't send the headers in time
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p, err = New(WithListener(listener), WithReadHeaderTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = p.OpenInbound(ctx)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("POST / HTTP/1.1\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1024))
+	require.Error(t, err)
+	if ne, ok := err.(net.Error); ok {
+		require.False(t, ne.Timeout(), "the server didn't close the connection")
+	}
+}
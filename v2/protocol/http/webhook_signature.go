@@ -0,0 +1,351 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+)
+
+// The headers of the Standard Webhooks signatures: https://www.standardwebhooks.com
+const (
+	WebhookID        = "Webhook-Id"
+	WebhookTimestamp = "Webhook-Timestamp"
+	WebhookSignature = "Webhook-Signature"
+)
+
+const (
+	// DefaultWebhookSignatureTolerance is the default maximum difference between the timestamp of a signed request
+	// and the time it's received, to reject the stale requests replayed later.
+	DefaultWebhookSignatureTolerance = 5 * time.Minute
+
+	webhookSecretPrefix     = "whsec_"
+	webhookSignatureVersion = "v1"
+)
+
+// ErrInvalidWebhookSignature is reported when a request is rejected because its signature is missing, invalid or stale.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// parseWebhookSecret decodes a secret in the Standard Webhooks format: base64, optionally prefixed with "whsec_"
+func parseWebhookSecret(secret string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(secret), webhookSecretPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook secret: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, errors.New("invalid webhook secret: empty")
+	}
+	return key, nil
+}
+
+// computeWebhookSignature returns the HMAC-SHA256 signature of the content "id.timestamp.body"
+func computeWebhookSignature(key []byte, id string, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(id))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// webhookSigner signs the outbound requests
+type webhookSigner struct {
+	key []byte
+}
+
+// prepare sets the webhook id of req, the id of the event in m if available, and makes its body readable
+// several times, to sign it before each attempt
+func (s *webhookSigner) prepare(req *http.Request, m binding.Message) error {
+	id := req.Header.Get(prefix + "id")
+	if mr, ok := m.(binding.MessageMetadataReader); ok {
+		if _, v := mr.GetAttribute(spec.ID); v != nil {
+			id = fmt.Sprint(v)
+		}
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	req.Header.Set(WebhookID, id)
+
+	if req.Body != nil && req.GetBody == nil {
+		if isStreamMessage(m) {
+			// The signature covers the whole body, so it can't be computed before streaming it
+			_ = req.Body.Close()
+			return errors.New("webhook signing requires the whole body, the data of a streamed event can't be signed")
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return err
+		}
+		return (*httpRequestWriter)(req).setBody(bytes.NewReader(body))
+	}
+	return nil
+}
+
+// isStreamMessage reports whether m, or a message it wraps, is a binding.StreamMessage
+func isStreamMessage(m binding.Message) bool {
+	for m != nil {
+		if _, ok := m.(*binding.StreamMessage); ok {
+			return true
+		}
+		w, ok := m.(binding.MessageWrapper)
+		if !ok {
+			return false
+		}
+		m = w.GetWrappedMessage()
+	}
+	return false
+}
+
+// sign sets the timestamp and the signature of req, covering the exact bytes of the body
+func (s *webhookSigner) sign(req *http.Request, now time.Time) error {
+	var body []byte
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		body, err = ioutil.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := computeWebhookSignature(s.key, req.Header.Get(WebhookID), timestamp, body)
+	req.Header.Set(WebhookTimestamp, timestamp)
+	req.Header.Set(WebhookSignature, webhookSignatureVersion+","+base64.StdEncoding.EncodeToString(signature))
+	return nil
+}
+
+// webhookVerifier verifies the signatures of the inbound requests
+type webhookVerifier struct {
+	// keys holds several secrets during their rotation
+	keys      [][]byte
+	tolerance time.Duration
+	replays   *webhookReplayCache
+}
+
+// verify checks the signature of req against its body, returning an error wrapping ErrInvalidWebhookSignature
+// if the signature doesn't match any key or if the timestamp is outside the tolerance
+func (v *webhookVerifier) verify(req *http.Request, body []byte, now time.Time) error {
+	id := req.Header.Get(WebhookID)
+	timestamp := req.Header.Get(WebhookTimestamp)
+	signatures := req.Header.Get(WebhookSignature)
+	if id == "" || timestamp == "" || signatures == "" {
+		return fmt.Errorf("%w: missing %s, %s or %s header", ErrInvalidWebhookSignature, WebhookID, WebhookTimestamp, WebhookSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", ErrInvalidWebhookSignature, timestamp)
+	}
+	if delta := now.Sub(time.Unix(seconds, 0)); delta > v.tolerance || delta < -v.tolerance {
+		return fmt.Errorf("%w: timestamp outside the tolerance of %v", ErrInvalidWebhookSignature, v.tolerance)
+	}
+
+	for _, key := range v.keys {
+		expected := computeWebhookSignature(key, id, timestamp, body)
+		for _, signature := range strings.Fields(signatures) {
+			parts := strings.SplitN(signature, ",", 2)
+			if len(parts) != 2 || parts[0] != webhookSignatureVersion {
+				continue
+			}
+			if decoded, err := base64.StdEncoding.DecodeString(parts[1]); err == nil && hmac.Equal(decoded, expected) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: no matching signature", ErrInvalidWebhookSignature)
+}
+
+// verifyRequest reads the body of req, bounded by limits if not nil, verifies its signature and replaces
+// the body with the read bytes. It returns the status code to reply with when the request is rejected.
+func (v *webhookVerifier) verifyRequest(req *http.Request, limits *binding.Limits) (int, error) {
+	var body []byte
+	if req.Body != nil {
+		var r io.Reader = req.Body
+		if limits != nil {
+			r = limits.LimitReader(r)
+		}
+		var err error
+		body, err = ioutil.ReadAll(r)
+		_ = req.Body.Close()
+		if errors.Is(err, binding.ErrLimitExceeded) {
+			return http.StatusRequestEntityTooLarge, err
+		} else if err != nil {
+			return http.StatusBadRequest, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	now := time.Now()
+	if err := v.verify(req, body, now); err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	// The signature is valid until the timestamp leaves the tolerance, so the message id is remembered until then
+	id := req.Header.Get(WebhookID)
+	seconds, _ := strconv.ParseInt(req.Header.Get(WebhookTimestamp), 10, 64)
+	if err := v.replays.reserve(id, time.Unix(seconds, 0).Add(v.tolerance), now); errors.Is(err, errWebhookReplayCacheFull) {
+		return http.StatusServiceUnavailable, err
+	} else if err != nil {
+		return http.StatusUnauthorized, err
+	}
+	return 0, nil
+}
+
+// delivered releases the message id of req if it wasn't accepted with status, so the sender can retry it.
+// A zero status means the handler didn't write the response, which is replied with 200 OK.
+func (v *webhookVerifier) delivered(req *http.Request, status int) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status/100 != 2 {
+		v.replays.release(req.Header.Get(WebhookID))
+	}
+}
+
+// webhookReplayCacheSize bounds the number of message ids remembered by a webhookReplayCache
+const webhookReplayCacheSize = 100000
+
+// errWebhookReplayCacheFull is returned by webhookReplayCache.reserve when it can't remember more ids
+var errWebhookReplayCacheFull = errors.New("too many webhook messages received within the tolerance")
+
+// webhookReplayCache remembers the ids of the received messages while their signature is valid, to reject
+// the replayed requests. When the cache is full, the new messages are rejected until ids expire: forgetting
+// ids still valid would allow to replay them.
+type webhookReplayCache struct {
+	mu      sync.Mutex
+	entries map[string]*replayEntry
+	// expiries holds the entries ordered by expiration time
+	expiries replayHeap
+}
+
+type replayEntry struct {
+	id      string
+	expires time.Time
+	// index is the index of the entry in replayHeap
+	index int
+}
+
+func newWebhookReplayCache() *webhookReplayCache {
+	return &webhookReplayCache{entries: make(map[string]*replayEntry)}
+}
+
+// reserve remembers id until expires. It returns an error wrapping ErrInvalidWebhookSignature if id is already
+// remembered, or errWebhookReplayCacheFull if the cache is full.
+func (c *webhookReplayCache) reserve(id string, expires time.Time, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.expiries) > 0 && !c.expiries[0].expires.After(now) {
+		delete(c.entries, heap.Pop(&c.expiries).(*replayEntry).id)
+	}
+
+	if _, ok := c.entries[id]; ok {
+		return fmt.Errorf("%w: message %q already received", ErrInvalidWebhookSignature, id)
+	}
+	if len(c.entries) >= webhookReplayCacheSize {
+		return errWebhookReplayCacheFull
+	}
+	e := &replayEntry{id: id, expires: expires}
+	c.entries[id] = e
+	heap.Push(&c.expiries, e)
+	return nil
+}
+
+// release forgets id, so the message can be received again
+func (c *webhookReplayCache) release(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[id]; ok {
+		delete(c.entries, id)
+		heap.Remove(&c.expiries, e.index)
+	}
+}
+
+// replayHeap is a min-heap of replayEntry by expiration time, see container/heap
+type replayHeap []*replayEntry
+
+func (h replayHeap) Len() int           { return len(h) }
+func (h replayHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h replayHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *replayHeap) Push(x interface{}) {
+	e := x.(*replayEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *replayHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+// statusResponseWriter records the status code of the response
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, if the underlying http.ResponseWriter does
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for http.ResponseController
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,311 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+const (
+	testWebhookSecret      = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+	testOtherWebhookSecret = "whsec_" + "c2VjcmV0LXRvLXJvdGF0ZQ=="
+)
+
+func TestComputeWebhookSignature(t *testing.T) {
+	// The test vector of the Standard Webhooks spec
+	key, err := parseWebhookSecret(testWebhookSecret)
+	require.NoError(t, err)
+	signature := computeWebhookSignature(key, "msg_p5jXN8AQM9LWM0D4loKWxJek", "1614265330", []byte(`{"test": 2432232314}`))
+	require.Equal(t, "g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", base64.StdEncoding.EncodeToString(signature))
+}
+
+func TestWebhookSigning(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := ioutil.ReadAll(req.Body)
+		requests = append(requests, req)
+		bodies = append(bodies, string(body))
+		if len(requests) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookSigning(testWebhookSecret),
+		WithCompression("gzip", 0))
+	require.NoError(t, err)
+	e := event.New()
+	e.SetID("id")
+	e.SetType("type")
+	e.SetSource("source")
+	ctx := cecontext.WithRetriesConstantBackoff(context.Background(), time.Millisecond, 1)
+	require.True(t, protocol.IsACK(p.Send(ctx, binding.ToMessage(&e))))
+
+	verifier := &webhookVerifier{keys: [][]byte{p.webhookSigner.key}, tolerance: DefaultWebhookSignatureTolerance}
+	require.Len(t, requests, 2)
+	for i, req := range requests {
+		require.Equal(t, "id", req.Header.Get(WebhookID))
+		// The signature covers the compressed bytes sent on each attempt
+		require.NoError(t, verifier.verify(req, []byte(bodies[i]), time.Now()))
+	}
+}
+
+func TestWebhookSigning_stream(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := New(WithTarget(server.URL), WithWebhookSigning(testWebhookSecret), WithCompression(EncodingGzip, 0))
+	require.NoError(t, err)
+	e := test.MinEvent()
+	result := p.Send(context.Background(), binding.NewStreamMessage(&e, ioutil.NopCloser(strings.NewReader("data"))))
+	require.EqualError(t, result, "webhook signing requires the whole body, the data of a streamed event can't be signed")
+	require.Zero(t, attempts)
+}
+
+func TestWebhookSignatureVerification(t *testing.T) {
+	sign := func(req *http.Request, secret string, timestamp time.Time) {
+		key, err := parseWebhookSecret(secret)
+		require.NoError(t, err)
+		req.Header.Set(WebhookID, "id")
+		require.NoError(t, (&webhookSigner{key: key}).sign(req, timestamp))
+	}
+	testCases := map[string]struct {
+		sign     func(req *http.Request)
+		wantCode int
+	}{
+		"valid": {
+			sign:     func(req *http.Request) { sign(req, testWebhookSecret, time.Now()) },
+			wantCode: http.StatusOK,
+		},
+		"rotated secret": {
+			sign:     func(req *http.Request) { sign(req, testOtherWebhookSecret, time.Now()) },
+			wantCode: http.StatusOK,
+		},
+		"several signatures": {
+			sign: func(req *http.Request) {
+				sign(req, testWebhookSecret, time.Now())
+				req.Header.Set(WebhookSignature, "v1,aW52YWxpZA== "+req.Header.Get(WebhookSignature))
+			},
+			wantCode: http.StatusOK,
+		},
+		"unknown secret": {
+			sign:     func(req *http.Request) { sign(req, "whsec_dW5rbm93bg==", time.Now()) },
+			wantCode: http.StatusUnauthorized,
+		},
+		"stale": {
+			sign:     func(req *http.Request) { sign(req, testWebhookSecret, time.Now().Add(-2*time.Minute)) },
+			wantCode: http.StatusUnauthorized,
+		},
+		"future": {
+			sign:     func(req *http.Request) { sign(req, testWebhookSecret, time.Now().Add(2*time.Minute)) },
+			wantCode: http.StatusUnauthorized,
+		},
+		"tampered id": {
+			sign: func(req *http.Request) {
+				sign(req, testWebhookSecret, time.Now())
+				req.Header.Set(WebhookID, "other")
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		"tampered timestamp": {
+			sign: func(req *http.Request) {
+				sign(req, testWebhookSecret, time.Now())
+				req.Header.Set(WebhookTimestamp, strconv.FormatInt(time.Now().Unix()+1, 10))
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		"unsigned": {
+			sign:     func(req *http.Request) {},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p, err := New(WithWebhookSignatureVerification(time.Minute, testWebhookSecret, testOtherWebhookSecret))
+			require.NoError(t, err)
+			server, rejections := newWebhookReceiver(t, p)
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("hello"))
+			require.NoError(t, err)
+			req.Header.Set("ce-specversion", "1.0")
+			req.Header.Set("ce-id", "1")
+			req.Header.Set("ce-type", "type")
+			req.Header.Set("ce-source", "source")
+			req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(strings.NewReader("hello")), nil }
+			tc.sign(req)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, tc.wantCode, resp.StatusCode)
+			if tc.wantCode == http.StatusUnauthorized {
+				require.True(t, errors.Is(<-rejections, ErrInvalidWebhookSignature))
+			}
+		})
+	}
+}
+
+func TestWebhookSignatureVerification_replay(t *testing.T) {
+	p, err := New(WithWebhookSignatureVerification(time.Minute, testWebhookSecret))
+	require.NoError(t, err)
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+
+	key, err := parseWebhookSecret(testWebhookSecret)
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set("ce-specversion", "1.0")
+	header.Set("ce-id", "1")
+	header.Set("ce-type", "type")
+	header.Set("ce-source", "source")
+	header.Set(WebhookID, "id")
+	signed, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("hello"))
+	require.NoError(t, err)
+	signed.Header = header
+	require.NoError(t, (&webhookSigner{key: key}).sign(signed, time.Now()))
+
+	// send sends the same signed request, answered with status by the receiver
+	send := func(status int) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("hello"))
+		require.NoError(t, err)
+		req.Header = header.Clone()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			msg, respFn, err := p.Respond(ctx)
+			if err == io.EOF {
+				// Rejected before the receiver
+				return
+			}
+			_ = msg.Finish(nil)
+			_ = respFn(ctx, nil, NewResult(status, ""))
+		}()
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		cancel()
+		<-done
+		return resp.StatusCode
+	}
+
+	// The message wasn't accepted, so it can be received again
+	require.Equal(t, http.StatusServiceUnavailable, send(http.StatusServiceUnavailable))
+	require.Equal(t, http.StatusOK, send(http.StatusOK))
+	require.Equal(t, http.StatusUnauthorized, send(http.StatusOK))
+}
+
+func TestWebhookReplayCache(t *testing.T) {
+	now := time.Now()
+	c := newWebhookReplayCache()
+	require.NoError(t, c.reserve("a", now.Add(time.Minute), now))
+	require.True(t, errors.Is(c.reserve("a", now.Add(time.Minute), now), ErrInvalidWebhookSignature))
+	c.release("a")
+	require.NoError(t, c.reserve("a", now.Add(time.Minute), now))
+
+	// Expired
+	require.NoError(t, c.reserve("a", now.Add(3*time.Minute), now.Add(2*time.Minute)))
+	require.Len(t, c.expiries, 1)
+
+	// An id expiring late doesn't hold the ids expiring before it
+	require.NoError(t, c.reserve("late", now.Add(time.Hour), now))
+	require.NoError(t, c.reserve("early", now.Add(time.Minute), now))
+	require.NoError(t, c.reserve("early", now.Add(3*time.Minute), now.Add(2*time.Minute)))
+	c.release("late")
+	require.Len(t, c.expiries, 2)
+
+	// Bounded, without forgetting the ids still valid
+	for i := len(c.entries); i < webhookReplayCacheSize; i++ {
+		require.NoError(t, c.reserve(strconv.Itoa(i), now.Add(time.Hour), now))
+	}
+	require.Len(t, c.entries, webhookReplayCacheSize)
+	require.True(t, errors.Is(c.reserve("b", now.Add(time.Hour), now), errWebhookReplayCacheFull))
+	require.True(t, errors.Is(c.reserve("a", now.Add(time.Hour), now), ErrInvalidWebhookSignature))
+
+	// Until they expire
+	require.NoError(t, c.reserve("b", now.Add(2*time.Hour), now.Add(time.Hour)))
+	require.Len(t, c.entries, 1)
+}
+
+func TestStatusResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	var w http.ResponseWriter = &statusResponseWriter{ResponseWriter: rec}
+	f, ok := w.(http.Flusher)
+	require.True(t, ok)
+	f.Flush()
+	require.True(t, rec.Flushed)
+	require.Equal(t, http.StatusOK, w.(*statusResponseWriter).status)
+}
+
+func TestWebhookSignatureVerification_notWritten(t *testing.T) {
+	v := &webhookVerifier{replays: newWebhookReplayCache()}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(WebhookID, "id")
+	now := time.Now()
+	require.NoError(t, v.replays.reserve("id", now.Add(time.Minute), now))
+
+	// The handler didn't write the response, replied with 200 OK, so the id is kept
+	v.delivered(req, 0)
+	require.Error(t, v.replays.reserve("id", now.Add(time.Minute), now))
+	v.delivered(req, http.StatusInternalServerError)
+	require.NoError(t, v.replays.reserve("id", now.Add(time.Minute), now))
+}
+
+func TestWebhookSignature_sdkReceiver(t *testing.T) {
+	receiver, err := New(WithWebhookSignatureVerification(0, testWebhookSecret), WithDecompression())
+	require.NoError(t, err)
+	server, _ := newWebhookReceiver(t, receiver)
+
+	sender, err := New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookSigning(testWebhookSecret),
+		WithCompression("gzip", 0))
+	require.NoError(t, err)
+	require.True(t, protocol.IsACK(sendTestEvent(sender)))
+
+	sender, err = New(WithTarget(server.URL), WithClient(http.Client{}), WithWebhookSigning(testOtherWebhookSecret))
+	require.NoError(t, err)
+	result := sendTestEvent(sender)
+	var httpResult *Result
+	require.True(t, errors.As(result, &httpResult))
+	require.Equal(t, http.StatusUnauthorized, httpResult.StatusCode)
+}
+
+func TestWithWebhookSigning(t *testing.T) {
+	_, err := New(WithWebhookSigning("whsec_not base64"))
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "http webhook signing option was given an invalid webhook secret"))
+	_, err = New(WithWebhookSignatureVerification(0))
+	require.EqualError(t, err, "http webhook signature verification option was given no secret")
+	_, err = New(WithWebhookSignatureVerification(0, testWebhookSecret, ""))
+	require.EqualError(t, err, "http webhook signature verification option was given an invalid webhook secret: empty")
+}